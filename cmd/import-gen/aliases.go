@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"terraform-provider-jira-automation/internal/client"
+)
+
+var customFieldPattern = regexp.MustCompile(`customfield_[0-9]+`)
+
+// collectFieldIDs adds the custom field IDs that rule's trigger and
+// components refer to, in field references or smart values, to seen.
+func collectFieldIDs(rule *client.Rule, seen map[string]bool) {
+	raws := append([]json.RawMessage{rule.Trigger}, rule.Components...)
+	for _, raw := range raws {
+		for _, id := range customFieldPattern.FindAllString(string(raw), -1) {
+			seen[id] = true
+		}
+	}
+}
+
+// aliasSuggestions lists the distinct field IDs in seen, followed by a
+// commented-out field_aliases block with placeholder names to adopt. The
+// names are field_<number> because the API doesn't return field names with
+// the rule; rename them before use.
+func aliasSuggestions(seen map[string]bool) string {
+	if len(seen) == 0 {
+		return "No custom field IDs found in the generated rules.\n"
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	// Numeric order: customfield_9999 before customfield_10000.
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Custom field IDs used by the generated rules (%d):\n", len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(&b, "  %s\n", id)
+	}
+	fmt.Fprintf(&b, "\nSuggested field_aliases (rename the placeholders, then uncomment):\n\n")
+	fmt.Fprintf(&b, "# provider \"jira-automation\" {\n")
+	fmt.Fprintf(&b, "#   field_aliases = {\n")
+	width := len(ids[len(ids)-1]) - len("custom") // Longest placeholder, for terraform fmt alignment.
+	for _, id := range ids {
+		fmt.Fprintf(&b, "#     %-*s = %q\n", width, strings.Replace(id, "customfield_", "field_", 1), id)
+	}
+	fmt.Fprintf(&b, "#   }\n")
+	fmt.Fprintf(&b, "# }\n")
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	// numbered returns lines "l1".."ln", with the given lines replaced.
+	numbered := func(n int, replace map[int]string) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			line, ok := replace[i]
+			if !ok {
+				line = fmt.Sprintf("l%d", i)
+			}
+			b.WriteString(line + "\n")
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "empty", a: "", b: "", want: ""},
+		{name: "identical", a: "x\ny\n", b: "x\ny\n", want: ""},
+		{
+			name: "insert into empty",
+			a:    "", b: "a\nb\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "insert in the middle",
+			a:    "1\n2\n3\n", b: "1\n2\nx\n3\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,4 @@\n 1\n 2\n+x\n 3\n",
+		},
+		{
+			name: "delete everything",
+			a:    "a\nb\n", b: "",
+			want: "--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "delete one line",
+			a:    "1\n2\n3\n", b: "1\n3\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,2 @@\n 1\n-2\n 3\n",
+		},
+		{
+			name: "mixed",
+			a:    "a\nb\nc\n", b: "a\nB\nc\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			a:    numbered(20, nil), b: numbered(20, map[int]string{2: "X", 18: "Y"}),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,5 +1,5 @@\n l1\n-l2\n+X\n l3\n l4\n l5\n" +
+				"@@ -15,6 +15,6 @@\n l15\n l16\n l17\n-l18\n+Y\n l19\n l20\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a", "b", tt.a, tt.b); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,9 +11,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"terraform-provider-jira-automation/internal/client"
+	"terraform-provider-jira-automation/internal/provider"
 )
 
 // extractUUIDFromURL extracts a rule UUID from a Jira Automation URL.
@@ -25,10 +31,35 @@ func extractUUIDFromURL(url string) string {
 	return ""
 }
 
+// parseSince parses a --since cutoff: a duration before now (72h, or 7d for
+// days), a date (2006-01-02, midnight UTC) or an RFC 3339 timestamp.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("--since must be a duration (72h, 7d), a date (2006-01-02) or an RFC 3339 timestamp, got %q", s)
+}
+
 func main() {
 	outDir := "."
-	labelFilter := ""
+	var filter client.RuleFilter
 	ruleID := ""
+	diffFile := ""
+	nameFrom := "slug"
+	check := false
+	suggestAliases := false
+	since := ""
 
 	// Parse flags.
 	args := os.Args[1:]
@@ -36,10 +67,30 @@ func main() {
 	for i := 0; i < len(args); i++ {
 		switch {
 		case (args[i] == "--label") && i+1 < len(args):
-			labelFilter = args[i+1]
+			filter.Label = args[i+1]
 			i++
 		case strings.HasPrefix(args[i], "--label="):
-			labelFilter = strings.TrimPrefix(args[i], "--label=")
+			filter.Label = strings.TrimPrefix(args[i], "--label=")
+		case (args[i] == "--state") && i+1 < len(args):
+			filter.State = strings.ToUpper(args[i+1])
+			i++
+		case strings.HasPrefix(args[i], "--state="):
+			filter.State = strings.ToUpper(strings.TrimPrefix(args[i], "--state="))
+		case (args[i] == "--trigger") && i+1 < len(args):
+			filter.Trigger = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--trigger="):
+			filter.Trigger = strings.TrimPrefix(args[i], "--trigger=")
+		case (args[i] == "--name") && i+1 < len(args):
+			filter.Name = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--name="):
+			filter.Name = strings.TrimPrefix(args[i], "--name=")
+		case (args[i] == "--since") && i+1 < len(args):
+			since = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--since="):
+			since = strings.TrimPrefix(args[i], "--since=")
 		case (args[i] == "--id") && i+1 < len(args):
 			ruleID = args[i+1]
 			i++
@@ -51,6 +102,20 @@ func main() {
 				log.Fatalf("Could not extract rule UUID from URL: %s", args[i+1])
 			}
 			i++
+		case (args[i] == "--diff") && i+1 < len(args):
+			diffFile = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--diff="):
+			diffFile = strings.TrimPrefix(args[i], "--diff=")
+		case (args[i] == "--name-from") && i+1 < len(args):
+			nameFrom = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--name-from="):
+			nameFrom = strings.TrimPrefix(args[i], "--name-from=")
+		case args[i] == "--check":
+			check = true
+		case args[i] == "--suggest-aliases":
+			suggestAliases = true
 		case strings.HasPrefix(args[i], "--url="):
 			ruleID = extractUUIDFromURL(strings.TrimPrefix(args[i], "--url="))
 			if ruleID == "" {
@@ -63,6 +128,31 @@ func main() {
 	if len(positional) > 0 {
 		outDir = positional[0]
 	}
+	if filter.State != "" && filter.State != "ENABLED" && filter.State != "DISABLED" {
+		log.Fatalf("--state must be ENABLED or DISABLED, got %q", filter.State)
+	}
+	if since != "" {
+		cutoff, err := parseSince(since, time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		filter.UpdatedSince = cutoff
+	}
+	if ruleID != "" && filter != (client.RuleFilter{}) {
+		log.Fatal("--label, --state, --trigger, --name and --since select rules to list, so they can't be combined with --id or --url")
+	}
+	if !nameStrategies[nameFrom] {
+		log.Fatalf("--name-from must be slug, uuid, or hash, got %q", nameFrom)
+	}
+	if diffFile != "" && ruleID == "" {
+		log.Fatal("--diff requires --id or --url to select the rule to compare")
+	}
+	if check && diffFile != "" {
+		log.Fatal("--check and --diff can't be combined")
+	}
+	if suggestAliases && (check || diffFile != "") {
+		log.Fatal("--suggest-aliases can't be combined with --check or --diff")
+	}
 
 	siteURL := envFirst("JIRA_SITE_URL", "ATLASSIAN_SITE_URL")
 	email := envFirst("JIRA_EMAIL", "ATLASSIAN_USER")
@@ -76,18 +166,48 @@ func main() {
 	if err != nil {
 		log.Fatalf("creating client: %v", err)
 	}
+	if apiBaseURL := os.Getenv("JIRA_API_BASE_URL"); apiBaseURL != "" {
+		c.BaseURL = client.AutomationBaseURL(apiBaseURL, c.CloudID)
+	}
 
-	// Single-rule mode: --id or --url.
-	if ruleID != "" {
-		importSingleRule(c, ruleID, outDir)
+	// Check mode: run generation in memory, don't write.
+	if check {
+		if checkRules(c, ruleID, filter) {
+			os.Exit(1)
+		}
 		return
 	}
 
-	// Bulk mode: list all rules, optionally filter by --label.
-	importAllRules(c, labelFilter, outDir)
+	// Diff mode: compare the live rule against an existing file, don't write.
+	if diffFile != "" {
+		if diffSingleRule(c, ruleID, diffFile, nameFrom) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Collect the custom field IDs of the generated rules to suggest aliases.
+	var fieldIDs map[string]bool
+	if suggestAliases {
+		fieldIDs = map[string]bool{}
+	}
+
+	if ruleID != "" {
+		// Single-rule mode: --id or --url.
+		importSingleRule(c, ruleID, outDir, nameFrom, fieldIDs)
+	} else {
+		// Bulk mode: list all rules, optionally filtered.
+		importAllRules(c, filter, outDir, nameFrom, fieldIDs)
+	}
+
+	if suggestAliases {
+		fmt.Printf("\n%s", aliasSuggestions(fieldIDs))
+	}
 }
 
-func importSingleRule(c *client.Client, uuid, outDir string) {
+// importSingleRule writes the HCL for one rule. If fieldIDs is non-nil, the
+// rule's custom field IDs are added to it.
+func importSingleRule(c *client.Client, uuid, outDir, nameFrom string, fieldIDs map[string]bool) {
 	fmt.Printf("Fetching rule %s ...\n", uuid)
 
 	rule, err := c.GetRule(uuid)
@@ -95,7 +215,7 @@ func importSingleRule(c *client.Client, uuid, outDir string) {
 		log.Fatalf("getting rule: %v", err)
 	}
 
-	resName := sanitize(rule.Name)
+	resName := resourceName(rule, nameFrom)
 	hcl := generateHCL(resName, rule)
 	filename := fmt.Sprintf("rule_%s.tf", resName)
 	path := filepath.Join(outDir, filename)
@@ -105,22 +225,60 @@ func importSingleRule(c *client.Client, uuid, outDir string) {
 	}
 
 	fmt.Printf("Generated %s\n", path)
+	if fieldIDs != nil {
+		collectFieldIDs(rule, fieldIDs)
+	}
+	printFallbackReasons(c, rule, "")
 	fmt.Printf("\nNext steps:\n")
 	fmt.Printf("  terraform plan   # review the import\n")
 	fmt.Printf("  terraform apply  # import into state\n")
 	fmt.Printf("  # Then remove the import block from %s\n", filename)
 }
 
-func importAllRules(c *client.Client, labelFilter, outDir string) {
-	summaries, err := c.ListRules()
+// resourceNamePattern finds the rule resource name in an existing .tf file.
+var resourceNamePattern = regexp.MustCompile(`resource\s+"jira-automation_rule"\s+"([^"]+)"`)
+
+// diffSingleRule prints a unified diff from the existing file to the HCL that
+// would be generated for the live rule. It reuses the file's resource name and
+// only emits the import block if the file still has one, so the diff shows
+// drift in the rule rather than in the scaffolding. Returns true if they differ.
+func diffSingleRule(c *client.Client, uuid, path, nameFrom string) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("reading %s: %v", path, err)
+	}
+
+	rule, err := c.GetRule(uuid)
+	if err != nil {
+		log.Fatalf("getting rule: %v", err)
+	}
+
+	resName := resourceName(rule, nameFrom)
+	if m := resourceNamePattern.FindSubmatch(existing); m != nil {
+		resName = string(m[1])
+	}
+	hcl := generateHCL(resName, rule)
+	if !strings.Contains(string(existing), "import {") {
+		hcl = strings.TrimPrefix(hcl, generateImportBlock(resName, rule.UUID)+"\n")
+	}
+
+	d := unifiedDiff(path, "jira:"+uuid, string(existing), hcl)
+	if d == "" {
+		return false
+	}
+	fmt.Print(d)
+	return true
+}
+
+// importAllRules writes the HCL for every rule matching filter. If fieldIDs
+// is non-nil, the generated rules' custom field IDs are added to it.
+func importAllRules(c *client.Client, filter client.RuleFilter, outDir, nameFrom string, fieldIDs map[string]bool) {
+	summaries, err := c.SearchRules(filter)
 	if err != nil {
 		log.Fatalf("listing rules: %v", err)
 	}
 
 	fmt.Printf("Found %d rules. Fetching full details...\n", len(summaries))
-	if labelFilter != "" {
-		fmt.Printf("Filtering by label: %s\n", labelFilter)
-	}
 
 	// Track used resource names to handle duplicates.
 	usedNames := map[string]int{}
@@ -135,13 +293,7 @@ func importAllRules(c *client.Client, labelFilter, outDir string) {
 			continue
 		}
 
-		// Filter by label if --label flag is set.
-		if labelFilter != "" && !hasLabel(rule.Labels, labelFilter) {
-			fmt.Printf("SKIP (no label %q)\n", labelFilter)
-			continue
-		}
-
-		resName := sanitize(rule.Name)
+		resName := resourceName(rule, nameFrom)
 		if count, exists := usedNames[resName]; exists {
 			usedNames[resName] = count + 1
 			resName = fmt.Sprintf("%s_%d", resName, count+1)
@@ -160,6 +312,10 @@ func importAllRules(c *client.Client, labelFilter, outDir string) {
 
 		generated++
 		fmt.Printf("-> %s\n", filename)
+		if fieldIDs != nil {
+			collectFieldIDs(rule, fieldIDs)
+		}
+		printFallbackReasons(c, rule, "      ")
 	}
 
 	if generated == 0 {
@@ -174,13 +330,78 @@ func importAllRules(c *client.Client, labelFilter, outDir string) {
 	fmt.Printf("  # Then remove the import blocks from each rule_*.tf file\n")
 }
 
-func hasLabel(labels []string, target string) bool {
-	for _, l := range labels {
-		if l == target {
-			return true
+// checkRules checks the selected rules in memory and reports the ones that
+// can't be fetched or generated. Rules that fall back to JSON still pass, with
+// the same notes as a real import. Returns true if any rule failed.
+func checkRules(c *client.Client, ruleID string, filter client.RuleFilter) bool {
+	var summaries []client.RuleSummary
+	if ruleID != "" {
+		summaries = []client.RuleSummary{{UUID: ruleID, Name: ruleID}}
+	} else {
+		var err error
+		summaries, err = c.SearchRules(filter)
+		if err != nil {
+			log.Fatalf("listing rules: %v", err)
+		}
+		fmt.Printf("Found %d rules. Checking...\n", len(summaries))
+	}
+
+	checked, failed := 0, 0
+	for i, s := range summaries {
+		rule, err := c.GetRule(s.UUID)
+		if err != nil {
+			fmt.Printf("  [%d/%d] %s ... FAIL (error: %v)\n", i+1, len(summaries), s.Name, err)
+			checked++
+			failed++
+			continue
+		}
+		checked++
+
+		fmt.Printf("  [%d/%d] %s ... ", i+1, len(summaries), rule.Name)
+		if problems := generationProblems(rule); len(problems) > 0 {
+			failed++
+			fmt.Printf("FAIL\n")
+			for _, p := range problems {
+				fmt.Printf("      - %s\n", p)
+			}
+			continue
+		}
+		fmt.Printf("ok\n")
+		printFallbackReasons(c, rule, "      ")
+	}
+
+	fmt.Printf("\nChecked %d rules, %d failed.\n", checked, failed)
+	return failed > 0
+}
+
+// generationProblems lists the parts of rule that generateHCL can't emit
+// faithfully: a trigger or component whose JSON doesn't decode is written as
+// null or dropped.
+func generationProblems(rule *client.Rule) []string {
+	var problems []string
+	if parseAndStrip(rule.Trigger) == nil {
+		problems = append(problems, "trigger: invalid or null JSON")
+	}
+	for i, raw := range rule.Components {
+		if parseAndStrip(raw) == nil {
+			problems = append(problems, fmt.Sprintf("component %d: invalid or null JSON", i))
 		}
 	}
-	return false
+	return problems
+}
+
+// printFallbackReasons notes on stderr which parts of rule can't use the
+// structured trigger/components blocks, so it's clear why they stay JSON and
+// which component types are missing structured support.
+func printFallbackReasons(c *client.Client, rule *client.Rule, indent string) {
+	reasons := provider.StructuredFallbackReasons(rule.Trigger, rule.Components, context.Background(), c.DebugLogPrefix)
+	if len(reasons) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%snote: no structured form for:\n", indent)
+	for _, r := range reasons {
+		fmt.Fprintf(os.Stderr, "%s  - %s\n", indent, r)
+	}
 }
 
 func envFirst(keys ...string) string {
@@ -192,6 +413,24 @@ func envFirst(keys ...string) string {
 	return ""
 }
 
+// nameStrategies are the accepted --name-from values. slug derives resource
+// names from the rule name; uuid and hash derive them from the rule UUID, so
+// they stay stable when rules are renamed or listed in a different order.
+var nameStrategies = map[string]bool{"slug": true, "uuid": true, "hash": true}
+
+// resourceName returns the Terraform resource name for rule under strategy.
+func resourceName(rule *client.Rule, strategy string) string {
+	switch strategy {
+	case "uuid":
+		return sanitize("r_" + rule.UUID)
+	case "hash":
+		sum := sha256.Sum256([]byte(rule.UUID))
+		return "r_" + hex.EncodeToString(sum[:4])
+	default:
+		return sanitize(rule.Name)
+	}
+}
+
 var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
 
 func sanitize(name string) string {
@@ -229,6 +468,9 @@ func generateHCL(resName string, rule *client.Rule) string {
 	fmt.Fprintf(&b, "resource \"jira-automation_rule\" %q {\n", resName)
 	fmt.Fprintf(&b, "  name    = %q\n", rule.Name)
 	fmt.Fprintf(&b, "  enabled = %v\n", enabled)
+	if rule.Description != "" {
+		fmt.Fprintf(&b, "\n  description = %q\n", rule.Description)
+	}
 
 	// scope is computed-only (assigned by the API), not emitted.
 	// labels are managed via internal API, not emitted.
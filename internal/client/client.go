@@ -2,26 +2,119 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
+// DefaultManagedLabelName is the label applied to Terraform-managed rules unless overridden.
+const DefaultManagedLabelName = "managed-by:terraform"
+
+// DefaultDebugLogPrefix prefixes the log actions generated for debug = "true".
+const DefaultDebugLogPrefix = "[DEBUG add_release_related_work] "
+
+// DefaultAPIBaseURL is the Atlassian API gateway serving the automation REST API.
+const DefaultAPIBaseURL = "https://api.atlassian.com"
+
+// AutomationBaseURL returns the automation REST API root for a cloud ID behind
+// the given API gateway (e.g. DefaultAPIBaseURL or a regional/FedRAMP host).
+func AutomationBaseURL(apiBaseURL, cloudID string) string {
+	return fmt.Sprintf("%s/automation/public/jira/%s/rest/v1", strings.TrimSuffix(apiBaseURL, "/"), cloudID)
+}
+
+// Client is safe for concurrent use: Terraform runs resource operations in
+// parallel against a single provider-configured client. Configuration fields
+// (including FieldAliases and ReverseAliases) must not be modified after the
+// provider finishes configuring; mutable caches are guarded by their own mutex.
 type Client struct {
-	BaseURL        string
-	SiteURL        string
-	CloudID        string
-	AccountID      string // Current user's Jira account ID, resolved at init.
-	Email          string
-	APIToken       string
-	WebhookUser    string
-	WebhookToken   string
-	HTTPClient     *http.Client
-	FieldAliases   map[string]string // alias → fieldID
-	ReverseAliases map[string]string // fieldID → alias
+	BaseURL          string
+	SiteURL          string
+	CloudID          string
+	AccountID        string // Current user's Jira account ID. Looked up on first use if empty; see CurrentAccountID.
+	Email            string
+	APIToken         string
+	WebhookUser      string
+	WebhookToken     string
+	HTTPClient       *http.Client
+	FieldAliases     map[string]string // alias → fieldID
+	ReverseAliases   map[string]string // fieldID → alias
+	ManageLabel      bool              // Tag managed rules with ManagedLabelName. Defaults to true.
+	ManagedLabelName string            // Label used to tag managed rules. Defaults to DefaultManagedLabelName.
+	JSONIndent       bool              // Store trigger_json/components_json read from the API indented.
+	DebugLogPrefix   string            // Prefix for generated debug log actions. Defaults to DefaultDebugLogPrefix.
+	AliasRawJSON     bool              // Also apply FieldAliases to string values in trigger_json/components_json.
+	DefaultEnabled   bool              // enabled of rules that don't set it. Defaults to true.
+	ReadOnly         bool              // Resources refuse to create, update or delete rules; reads still work.
+	SystemAuthors    []string          // Account IDs whose rules are treated as system-owned.
+
+	// RequestHook and ResponseHook, when set, observe every request sent,
+	// retries included, e.g. for metrics or to assert call sequences in tests.
+	// RequestHook runs before the request is sent and ResponseHook after, with
+	// a nil response if err is set. They must not consume the response body.
+	RequestHook  func(*http.Request)
+	ResponseHook func(*http.Response, error)
+
+	ctx     context.Context // Bounds every request; set by WithContext. Nil means no deadline.
+	labels  *labelCache     // Shared with copies made by WithContext. Nil (clients not built by New) disables caching.
+	account *accountCache   // Shared with copies made by WithContext. Nil (clients not built by New) disables caching.
+}
+
+// accountCache holds the account ID looked up by CurrentAccountID.
+type accountCache struct {
+	mu sync.Mutex
+	id string
+}
+
+// labelCache holds each project's labels, populated lazily by LabelID. mu
+// only guards the map; each project has its own lock, held while its labels
+// are listed, so lookups in other projects don't wait on that request.
+type labelCache struct {
+	mu        sync.Mutex
+	byProject map[string]*projectLabels
+}
+
+// projectLabels is one project's entry in labelCache.
+type projectLabels struct {
+	mu     sync.Mutex
+	loaded bool
+	labels []Label
+}
+
+// project returns the cache entry for projectID, creating it if needed.
+func (lc *labelCache) project(projectID string) *projectLabels {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	p, ok := lc.byProject[projectID]
+	if !ok {
+		p = &projectLabels{}
+		lc.byProject[projectID] = p
+	}
+	return p
+}
+
+// WithContext returns a shallow copy of c whose requests, including retries,
+// are bound to ctx, so callers can apply per-operation deadlines. The copy
+// shares configuration and the label cache with c.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// requestContext returns the context requests are bound to.
+func (c *Client) requestContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // TenantInfo is the response from /_edge/tenant_info.
@@ -31,10 +124,17 @@ type TenantInfo struct {
 
 // RuleSummary is a single entry returned by GET /rule/summary.
 type RuleSummary struct {
-	UUID    string `json:"uuid"`
-	Name    string `json:"name"`
-	State   string `json:"state"`
-	Enabled bool   `json:"enabled"`
+	UUID    string   `json:"uuid"`
+	Name    string   `json:"name"`
+	State   string   `json:"state"`
+	Enabled bool     `json:"enabled"`
+	Labels  []string `json:"labels,omitempty"`
+	Updated float64  `json:"updated,omitempty"` // Last update, in Unix seconds; 0 if the listing doesn't include it.
+}
+
+// unixTime converts an API timestamp in fractional Unix seconds to a time.
+func unixTime(secs float64) time.Time {
+	return time.UnixMilli(int64(secs * 1000))
 }
 
 // ListRulesResponse is the paginated response from GET /rule/summary.
@@ -50,13 +150,38 @@ type GetRuleResponse struct {
 
 // Rule is the full rule object from GET /rule/{uuid}.
 type Rule struct {
-	UUID          string            `json:"uuid,omitempty"`
-	Name          string            `json:"name"`
-	State         string            `json:"state,omitempty"`
-	RuleScopeARIs []string          `json:"ruleScopeARIs,omitempty"`
-	Labels        []string          `json:"labels,omitempty"`
-	Trigger       json.RawMessage   `json:"trigger"`
-	Components    []json.RawMessage `json:"components"`
+	UUID            string            `json:"uuid,omitempty"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description,omitempty"`
+	State           string            `json:"state,omitempty"`
+	AuthorAccountID string            `json:"authorAccountId,omitempty"`
+	RuleScopeARIs   []string          `json:"ruleScopeARIs,omitempty"`
+	Labels          []string          `json:"labels,omitempty"`
+	Updated         float64           `json:"updated,omitempty"` // Last update, in Unix seconds.
+	Actor           *RuleActor        `json:"actor,omitempty"`
+	Trigger         json.RawMessage   `json:"trigger"`
+	Components      []json.RawMessage `json:"components"`
+}
+
+// Actor types for RuleActor.Type.
+const (
+	ActorAccountID      = "ACCOUNT_ID"      // Runs as the account in RuleActor.Actor.
+	ActorEventInitiator = "EVENT_INITIATOR" // Runs as the user who triggered the rule.
+)
+
+// RuleActor is who a rule's actions run as, which decides their permissions
+// and e.g. who comments are posted by.
+type RuleActor struct {
+	Type  string `json:"type"`
+	Actor string `json:"actor,omitempty"` // Account ID for ActorAccountID.
+}
+
+// IsSystemOwned reports whether the rule was authored by one of
+// systemAuthors, the accounts that create Atlassian-managed rules on the
+// site. The API has no system or hidden marker, so the author is all there is
+// to go on. Editing such rules can break the Jira features that rely on them.
+func (r *Rule) IsSystemOwned(systemAuthors []string) bool {
+	return r.AuthorAccountID != "" && slices.Contains(systemAuthors, r.AuthorAccountID)
 }
 
 // GetRuleRaw returns the raw JSON for a rule (without the envelope).
@@ -88,10 +213,14 @@ func (c *Client) GetRuleRaw(uuid string) (json.RawMessage, error) {
 
 // CreateRuleRequest is the payload for POST /rule.
 type CreateRuleRequest struct {
-	Name       string
-	ProjectID  string // Optional; used to build project-scoped ARIs.
-	Trigger    json.RawMessage
-	Components []json.RawMessage
+	Name        string
+	Description string
+	ProjectID   string   // Optional; used to build project-scoped ARIs.
+	ScopeARIs   []string // Optional; sent verbatim as ruleScopeARIs, overriding ProjectID.
+	Trigger     json.RawMessage
+	Components  []json.RawMessage
+	Enabled     bool       // Create the rule ENABLED rather than DISABLED.
+	Actor       *RuleActor // Optional; nil runs the rule as the client's account.
 }
 
 // CreateRuleResponse is the response from POST /rule.
@@ -103,9 +232,22 @@ type CreateRuleResponse struct {
 
 // UpdateRuleRequest is the payload for PUT /rule/{uuid}.
 type UpdateRuleRequest struct {
-	Name       string            `json:"name"`
-	Trigger    json.RawMessage   `json:"trigger"`
-	Components []json.RawMessage `json:"components"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Trigger     json.RawMessage   `json:"trigger"`
+	Components  []json.RawMessage `json:"components"`
+	Actor       *RuleActor        `json:"actor,omitempty"` // Optional; nil keeps the rule's current actor.
+
+	// PreserveComponentIDs keeps the current IDs of components that match the
+	// update by position, component and type, instead of having the API
+	// recreate every component.
+	PreserveComponentIDs bool `json:"-"`
+
+	// ComponentIDSources pairs components by something other than position
+	// when PreserveComponentIDs is set: for each of Components, the index of
+	// the current component whose IDs it keeps, or -1 for none. Nil pairs
+	// them by position.
+	ComponentIDSources []int `json:"-"`
 }
 
 // SetRuleStateRequest is the payload for PUT /rule/{uuid}/state.
@@ -136,6 +278,14 @@ func New(siteURL, email, apiToken, webhookUser, webhookToken string, aliases map
 		return nil, fmt.Errorf("tenant info returned %d: %s", resp.StatusCode, string(body))
 	}
 
+	// A login page served in place of the JSON (SSO, a proxy, or a site URL
+	// that isn't a Jira Cloud site) otherwise fails with an opaque decode error.
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "text/html" {
+		return nil, fmt.Errorf("tenant info at %s returned an HTML page instead of JSON (final URL %s). "+
+			"Check that site_url is your Jira Cloud site (https://<site>.atlassian.net) and that no SSO login or proxy intercepts requests to it",
+			tenantURL, resp.Request.URL)
+	}
+
 	var tenant TenantInfo
 	if err := json.NewDecoder(resp.Body).Decode(&tenant); err != nil {
 		return nil, fmt.Errorf("decoding tenant info: %w", err)
@@ -144,103 +294,317 @@ func New(siteURL, email, apiToken, webhookUser, webhookToken string, aliases map
 		return nil, fmt.Errorf("empty cloudId from tenant info")
 	}
 
-	baseURL := fmt.Sprintf("https://api.atlassian.com/automation/public/jira/%s/rest/v1", tenant.CloudID)
+	baseURL := AutomationBaseURL(DefaultAPIBaseURL, tenant.CloudID)
 
-	// Resolve the current user's account ID for rule authorship fields.
-	myselfURL := siteURL + "/rest/api/3/myself"
-	myselfReq, err := http.NewRequest(http.MethodGet, myselfURL, nil)
+	// Copy the caller's aliases so both maps are owned by the client and never
+	// mutated after construction; concurrent readers need no locking.
+	fieldAliases := make(map[string]string, len(aliases))
+	reverse := make(map[string]string, len(aliases))
+	for alias, fieldID := range aliases {
+		fieldAliases[alias] = fieldID
+		reverse[fieldID] = alias
+	}
+
+	c := &Client{
+		BaseURL:          baseURL,
+		SiteURL:          siteURL,
+		CloudID:          tenant.CloudID,
+		Email:            email,
+		APIToken:         apiToken,
+		WebhookUser:      webhookUser,
+		WebhookToken:     webhookToken,
+		HTTPClient:       httpClient,
+		FieldAliases:     fieldAliases,
+		ReverseAliases:   reverse,
+		ManageLabel:      true,
+		DefaultEnabled:   true,
+		ManagedLabelName: DefaultManagedLabelName,
+		DebugLogPrefix:   DefaultDebugLogPrefix,
+		labels:           &labelCache{byProject: map[string]*projectLabels{}},
+		account:          &accountCache{},
+	}
+	return c, nil
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req = req.WithContext(c.requestContext())
+	req.SetBasicAuth(c.Email, c.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.RequestHook != nil {
+		c.RequestHook(req)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if c.ResponseHook != nil {
+		c.ResponseHook(resp, err)
+	}
+	return resp, err
+}
+
+// Myself is the subset of /rest/api/3/myself the provider uses.
+type Myself struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+}
+
+// WhoAmI returns the user the client authenticates as. It doubles as a
+// credentials and connectivity check.
+func (c *Client) WhoAmI() (*Myself, error) {
+	req, err := http.NewRequest(http.MethodGet, c.SiteURL+"/rest/api/3/myself", nil)
 	if err != nil {
 		return nil, fmt.Errorf("building myself request: %w", err)
 	}
-	myselfReq.SetBasicAuth(email, apiToken)
-	myselfReq.Header.Set("Accept", "application/json")
-	myselfResp, err := httpClient.Do(myselfReq)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetching current user: %w", err)
 	}
-	defer myselfResp.Body.Close()
+	defer resp.Body.Close()
 
-	if myselfResp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(myselfResp.Body)
-		return nil, fmt.Errorf("myself returned %d: %s", myselfResp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("myself returned %d: %s", resp.StatusCode, string(body))
 	}
 
-	var myself struct {
-		AccountID string `json:"accountId"`
-	}
-	if err := json.NewDecoder(myselfResp.Body).Decode(&myself); err != nil {
+	var me Myself
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
 		return nil, fmt.Errorf("decoding myself response: %w", err)
 	}
-	if myself.AccountID == "" {
+	if me.AccountID == "" {
 		return nil, fmt.Errorf("empty accountId from /rest/api/3/myself")
 	}
+	return &me, nil
+}
 
-	if aliases == nil {
-		aliases = map[string]string{}
+// CurrentAccountID returns AccountID, or else the account the client
+// authenticates as. The lookup happens on first use rather than in New, so
+// read-only callers (listing and getting rules, import-gen) work with tokens
+// that can't read the current user.
+func (c *Client) CurrentAccountID() (string, error) {
+	if c.AccountID != "" {
+		return c.AccountID, nil
 	}
-	reverse := make(map[string]string, len(aliases))
-	for alias, fieldID := range aliases {
-		reverse[fieldID] = alias
+	if c.account == nil {
+		me, err := c.WhoAmI()
+		if err != nil {
+			return "", err
+		}
+		return me.AccountID, nil
 	}
-
-	return &Client{
-		BaseURL:        baseURL,
-		SiteURL:        siteURL,
-		CloudID:        tenant.CloudID,
-		AccountID:      myself.AccountID,
-		Email:          email,
-		APIToken:       apiToken,
-		WebhookUser:    webhookUser,
-		WebhookToken:   webhookToken,
-		HTTPClient:     httpClient,
-		FieldAliases:   aliases,
-		ReverseAliases: reverse,
-	}, nil
-}
-
-func (c *Client) do(req *http.Request) (*http.Response, error) {
-	req.SetBasicAuth(c.Email, c.APIToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	return c.HTTPClient.Do(req)
+	c.account.mu.Lock()
+	defer c.account.mu.Unlock()
+	if c.account.id == "" {
+		me, err := c.WhoAmI()
+		if err != nil {
+			return "", err
+		}
+		c.account.id = me.AccountID
+	}
+	return c.account.id, nil
 }
 
 // ListRules returns all rule summaries, handling cursor pagination.
 func (c *Client) ListRules() ([]RuleSummary, error) {
 	var all []RuleSummary
-	url := c.BaseURL + "/rule/summary"
+	err := c.ForEachRule(func(rule RuleSummary) error {
+		all = append(all, rule)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// ForEachRule calls fn for each rule summary, fetching one page at a time so
+// large sites needn't be held in memory. It stops at the first error from fn
+// and returns it unwrapped, so callers can stop early with a sentinel error.
+func (c *Client) ForEachRule(fn func(RuleSummary) error) error {
+	return forEachPage(c.ListRulesPage, fn)
+}
 
+// forEachPage calls fn for each rule summary on the pages listPage returns,
+// following their cursors.
+func forEachPage(listPage func(cursor string) (ListRulesResponse, error), fn func(RuleSummary) error) error {
+	cursor := ""
 	for {
-		req, err := http.NewRequest(http.MethodGet, url, nil)
+		page, err := listPage(cursor)
 		if err != nil {
-			return nil, fmt.Errorf("building list rules request: %w", err)
+			return err
 		}
-
-		resp, err := c.do(req)
-		if err != nil {
-			return nil, fmt.Errorf("listing rules: %w", err)
+		for _, rule := range page.Data {
+			if err := fn(rule); err != nil {
+				return err
+			}
+		}
+		if page.Cursor == nil || *page.Cursor == "" {
+			return nil
 		}
-		defer resp.Body.Close()
+		cursor = *page.Cursor
+	}
+}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("list rules returned %d: %s", resp.StatusCode, string(body))
+// ListRulesPage returns one page of rule summaries. Pass an empty cursor for
+// the first page and the returned Cursor for the next; a nil or empty Cursor
+// means the page is the last.
+func (c *Client) ListRulesPage(cursor string) (ListRulesResponse, error) {
+	u := c.BaseURL + "/rule/summary"
+	if cursor != "" {
+		u += "?cursor=" + url.QueryEscape(cursor)
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return ListRulesResponse{}, fmt.Errorf("building list rules request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return ListRulesResponse{}, fmt.Errorf("listing rules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return ListRulesResponse{}, fmt.Errorf("list rules returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var page ListRulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return ListRulesResponse{}, fmt.Errorf("decoding list rules response: %w", err)
+	}
+	return page, nil
+}
+
+// RuleFilter selects the rules SearchRules returns. Empty fields match every
+// rule.
+type RuleFilter struct {
+	ProjectID string // Only this project's rules, searched by their project scope instead of listed site-wide.
+	State     string // ENABLED or DISABLED.
+	Trigger   string // Trigger type, e.g. jira.issue.event.trigger:created.
+	Name      string // Case-insensitive substring of the rule name.
+	Label     string // Label name, matched against the labels in the summaries.
+
+	// UpdatedSince keeps only rules last updated at or after it. A rule whose
+	// summary lacks the update time is fetched in full, and kept if that
+	// lacks it too.
+	UpdatedSince time.Time
+}
+
+// SearchRules returns the summaries of the rules matching f. ProjectID, State
+// and Trigger are filtered by the API's rule search; Name, Label and
+// UpdatedSince are checked here.
+func (c *Client) SearchRules(f RuleFilter) ([]RuleSummary, error) {
+	var rules []RuleSummary
+	var err error
+	if f.ProjectID != "" || f.State != "" || f.Trigger != "" {
+		req := searchRulesRequest{State: f.State, Trigger: f.Trigger, Limit: 100}
+		if f.ProjectID != "" {
+			req.Scope = c.projectARI(f.ProjectID)
 		}
+		rules, err = c.searchRules(req)
+	} else {
+		rules, err = c.ListRules()
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		var page ListRulesResponse
-		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
-			return nil, fmt.Errorf("decoding list rules response: %w", err)
+	var matched []RuleSummary
+	for _, r := range rules {
+		if f.Name != "" && !strings.Contains(strings.ToLower(r.Name), strings.ToLower(f.Name)) {
+			continue
+		}
+		if f.Label != "" && !slices.Contains(r.Labels, f.Label) {
+			continue
 		}
+		if !f.UpdatedSince.IsZero() {
+			updated := r.Updated
+			if updated == 0 {
+				// Only the full rule has the update time.
+				rule, err := c.GetRule(r.UUID)
+				if err != nil {
+					return nil, fmt.Errorf("reading rule %s: %w", r.UUID, err)
+				}
+				updated = rule.Updated
+			}
+			if updated != 0 && unixTime(updated).Before(f.UpdatedSince) {
+				continue
+			}
+		}
+		matched = append(matched, r)
+	}
+	return matched, nil
+}
 
-		all = append(all, page.Data...)
+// searchRulesRequest is the payload for POST /rule/summary. The API needs at
+// least one of trigger, state, scope or limit.
+type searchRulesRequest struct {
+	Cursor  string `json:"cursor,omitempty"`
+	Trigger string `json:"trigger,omitempty"`
+	State   string `json:"state,omitempty"`
+	Scope   string `json:"scope,omitempty"` // Scope ARI, e.g. a project's.
+	Limit   int    `json:"limit"`
+}
 
-		if page.Cursor == nil || *page.Cursor == "" {
-			break
-		}
-		url = c.BaseURL + "/rule/summary?cursor=" + *page.Cursor
+// ListRulesForProject returns the summaries of rules scoped to a single
+// project, searching by the project's scope ARI instead of listing the site.
+func (c *Client) ListRulesForProject(projectID string) ([]RuleSummary, error) {
+	rules, err := c.searchRules(searchRulesRequest{Scope: c.projectARI(projectID), Limit: 100})
+	if err != nil {
+		return nil, fmt.Errorf("listing rules for project %s: %w", projectID, err)
 	}
+	return rules, nil
+}
 
-	return all, nil
+// searchRules returns the summaries of all the rules matching search,
+// following its pages.
+func (c *Client) searchRules(search searchRulesRequest) ([]RuleSummary, error) {
+	var rules []RuleSummary
+	err := forEachPage(func(cursor string) (ListRulesResponse, error) {
+		search.Cursor = cursor
+		return c.searchRulesPage(search)
+	}, func(rule RuleSummary) error {
+		rules = append(rules, rule)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// projectARI returns the scope ARI of a project on c's site.
+func (c *Client) projectARI(projectID string) string {
+	return fmt.Sprintf("ari:cloud:jira:%s:project/%s", c.CloudID, projectID)
+}
+
+// searchRulesPage returns one page of the rule summaries matching search.
+func (c *Client) searchRulesPage(search searchRulesRequest) (ListRulesResponse, error) {
+	body, err := json.Marshal(search)
+	if err != nil {
+		return ListRulesResponse{}, fmt.Errorf("marshaling search rules request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+"/rule/summary", bytes.NewReader(body))
+	if err != nil {
+		return ListRulesResponse{}, fmt.Errorf("building search rules request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return ListRulesResponse{}, fmt.Errorf("searching rules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return ListRulesResponse{}, fmt.Errorf("search rules returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var page ListRulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return ListRulesResponse{}, fmt.Errorf("decoding search rules response: %w", err)
+	}
+	return page, nil
 }
 
 // GetRule returns the full rule config for a given UUID.
@@ -261,7 +625,8 @@ func (c *Client) GetRule(uuid string) (*Rule, error) {
 // CreateRule creates a new automation rule and returns the UUID.
 // The API requires several fields beyond name/trigger/components:
 // state, notifyOnError, canOtherRuleTrigger, authorAccountId, actor,
-// writeAccessType, and ruleScopeARIs. These are populated automatically.
+// writeAccessType, and ruleScopeARIs. These are populated automatically;
+// state comes from rule.Enabled and actor from rule.Actor.
 func (c *Client) CreateRule(rule CreateRuleRequest) (string, error) {
 	// Parse trigger and components into generic types for the payload.
 	var trigger interface{}
@@ -278,34 +643,78 @@ func (c *Client) CreateRule(rule CreateRuleRequest) (string, error) {
 		components = append(components, v)
 	}
 
-	// Build scope ARIs.
+	// Build scope ARIs, unless given.
 	var scopeARIs []string
-	if rule.ProjectID != "" {
-		scopeARIs = []string{
-			fmt.Sprintf("ari:cloud:jira:%s:project/%s", c.CloudID, rule.ProjectID),
-		}
-	} else {
+	switch {
+	case len(rule.ScopeARIs) > 0:
+		scopeARIs = rule.ScopeARIs
+	case rule.ProjectID != "":
+		scopeARIs = []string{c.projectARI(rule.ProjectID)}
+	default:
 		scopeARIs = []string{
 			fmt.Sprintf("ari:cloud:jira::site/%s", c.CloudID),
 		}
 	}
 
+	state := "DISABLED"
+	if rule.Enabled {
+		state = "ENABLED"
+	}
+
+	accountID, err := c.CurrentAccountID()
+	if err != nil {
+		return "", fmt.Errorf("resolving rule author: %w", err)
+	}
+	actor, err := c.actorPayload(rule.Actor)
+	if err != nil {
+		return "", err
+	}
+
 	// Build the full rule payload with all required fields.
 	payload := map[string]interface{}{
 		"name":                rule.Name,
-		"state":               "DISABLED", // Create disabled; enable via SetRuleState after.
+		"state":               state,
 		"notifyOnError":       "FIRSTERROR",
 		"canOtherRuleTrigger": false,
-		"authorAccountId":     c.AccountID,
-		"actor":               map[string]string{"type": "ACCOUNT_ID", "actor": c.AccountID},
+		"authorAccountId":     accountID,
+		"actor":               actor,
 		"writeAccessType":     "OWNER_ONLY",
 		"trigger":             trigger,
 		"components":          components,
 		"ruleScopeARIs":       scopeARIs,
 	}
+	if rule.Description != "" {
+		payload["description"] = rule.Description
+	}
+
+	ruleJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshaling create rule request: %w", err)
+	}
+	return c.CreateRuleRaw(ruleJSON)
+}
+
+// actorPayload returns the actor to send for a, defaulting to the client's
+// account when a is nil or is ActorAccountID without an account.
+func (c *Client) actorPayload(a *RuleActor) (RuleActor, error) {
+	if a != nil && (a.Type != ActorAccountID || a.Actor != "") {
+		return *a, nil
+	}
+	accountID, err := c.CurrentAccountID()
+	if err != nil {
+		return RuleActor{}, fmt.Errorf("resolving rule actor: %w", err)
+	}
+	return RuleActor{Type: ActorAccountID, Actor: accountID}, nil
+}
 
-	envelope := map[string]interface{}{"rule": payload}
-	body, err := json.Marshal(envelope)
+// CreateRuleRaw POSTs ruleJSON as the complete rule, wrapped in the required
+// {"rule": ...} envelope, and returns the new rule's UUID. Unlike CreateRule
+// it fills in nothing, so ruleJSON must carry the required fields itself (e.g.
+// a GetRuleRaw result with uuid, created and updated removed).
+func (c *Client) CreateRuleRaw(ruleJSON json.RawMessage) (string, error) {
+	body, err := json.Marshal(struct {
+		Rule json.RawMessage `json:"rule"`
+	}{ruleJSON})
 	if err != nil {
 		return "", fmt.Errorf("marshaling create rule request: %w", err)
 	}
@@ -336,25 +745,94 @@ func (c *Client) CreateRule(rule CreateRuleRequest) (string, error) {
 	if uuid == "" {
 		uuid = result.RuleUUID
 	}
+	if uuid == "" {
+		return "", fmt.Errorf("create rule response contained neither uuid nor ruleUuid")
+	}
 
 	return uuid, nil
 }
 
+// BulkCreateResult is the outcome of one rule in a BulkCreateRules call.
+type BulkCreateResult struct {
+	Name string
+	UUID string // Empty if creation failed.
+	Err  error  // Creation or labeling error, if any.
+}
+
+// BulkCreateRules creates many rules, running up to concurrency creates at a time
+// (values below 1 mean sequential). When ManageLabel is set, project-scoped rules
+// are tagged with ManagedLabelName; the label lookup goes through the shared
+// per-project cache so each project is listed once regardless of rule count.
+// Each rule is created in the state its Enabled field asks for, as with
+// CreateRule. Results are returned in input order.
+func (c *Client) BulkCreateRules(rules []CreateRuleRequest, concurrency int) []BulkCreateResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BulkCreateResult, len(rules))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, rule := range rules {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rule CreateRuleRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.createAndLabel(rule)
+		}(i, rule)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (c *Client) createAndLabel(rule CreateRuleRequest) BulkCreateResult {
+	result := BulkCreateResult{Name: rule.Name}
+
+	uuid, err := c.CreateRule(rule)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.UUID = uuid
+
+	if !c.ManageLabel || rule.ProjectID == "" {
+		return result
+	}
+	labelID, err := c.LabelID(rule.ProjectID, c.ManagedLabelName)
+	if err != nil {
+		result.Err = fmt.Errorf("looking up label %q: %w", c.ManagedLabelName, err)
+		return result
+	}
+	if labelID == 0 {
+		result.Err = fmt.Errorf("label %q not found in project %s", c.ManagedLabelName, rule.ProjectID)
+		return result
+	}
+	if err := c.AddLabelToRule(rule.ProjectID, uuid, labelID); err != nil {
+		result.Err = err
+	}
+	return result
+}
+
 // UpdateRule updates an existing automation rule.
 // It performs a read-modify-write: fetches the current rule to get all API fields,
 // merges in the Terraform-managed fields, strips component IDs (so the API recreates
 // them), and PUTs the complete rule wrapped in the required {"rule": ...} envelope.
-func (c *Client) UpdateRule(uuid string, update UpdateRuleRequest) error {
+// It returns the rule as sent, so callers needn't fetch it again. That lacks
+// what the API generates on save, such as an incoming webhook trigger's URL.
+func (c *Client) UpdateRule(uuid string, update UpdateRuleRequest) (*Rule, error) {
 	// 1. Fetch current rule as raw JSON to preserve all API-managed fields.
 	raw, err := c.GetRuleRaw(uuid)
 	if err != nil {
-		return fmt.Errorf("reading current rule for update: %w", err)
+		return nil, fmt.Errorf("reading current rule for update: %w", err)
 	}
 
 	// 2. Unmarshal into a generic map so we can merge fields.
 	var ruleMap map[string]interface{}
 	if err := json.Unmarshal(raw, &ruleMap); err != nil {
-		return fmt.Errorf("parsing current rule: %w", err)
+		return nil, fmt.Errorf("parsing current rule: %w", err)
 	}
 
 	// 3. Remove read-only fields that the API won't accept on write.
@@ -363,11 +841,14 @@ func (c *Client) UpdateRule(uuid string, update UpdateRuleRequest) error {
 	delete(ruleMap, "updated")
 
 	// 4. Merge Terraform-managed fields.
+	currentTrigger := ruleMap["trigger"]
+	currentComponents, _ := ruleMap["components"].([]interface{})
 	ruleMap["name"] = update.Name
+	ruleMap["description"] = update.Description
 
 	var trigger interface{}
 	if err := json.Unmarshal(update.Trigger, &trigger); err != nil {
-		return fmt.Errorf("parsing trigger: %w", err)
+		return nil, fmt.Errorf("parsing trigger: %w", err)
 	}
 	// Strip IDs from trigger.
 	stripComponentIDs(trigger)
@@ -377,16 +858,59 @@ func (c *Client) UpdateRule(uuid string, update UpdateRuleRequest) error {
 	for _, comp := range update.Components {
 		var c interface{}
 		if err := json.Unmarshal(comp, &c); err != nil {
-			return fmt.Errorf("parsing component: %w", err)
+			return nil, fmt.Errorf("parsing component: %w", err)
 		}
 		stripComponentIDs(c)
 		components = append(components, c)
 	}
+	if update.PreserveComponentIDs {
+		copyComponentIDs(currentTrigger, trigger)
+		for i := range components {
+			src := i
+			if update.ComponentIDSources != nil {
+				src = -1
+				if i < len(update.ComponentIDSources) {
+					src = update.ComponentIDSources[i]
+				}
+			}
+			if src >= 0 && src < len(currentComponents) {
+				copyComponentIDs(currentComponents[src], components[i])
+			}
+		}
+	}
 	ruleMap["components"] = components
+	if update.Actor != nil {
+		actor, err := c.actorPayload(update.Actor)
+		if err != nil {
+			return nil, err
+		}
+		ruleMap["actor"] = actor
+	}
+
+	ruleJSON, err := json.Marshal(ruleMap)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling update rule request: %w", err)
+	}
+	if err := c.UpdateRuleRaw(uuid, ruleJSON); err != nil {
+		return nil, err
+	}
+
+	var rule Rule
+	if err := json.Unmarshal(ruleJSON, &rule); err != nil {
+		return nil, fmt.Errorf("decoding updated rule: %w", err)
+	}
+	rule.UUID = uuid
+	return &rule, nil
+}
 
-	// 5. Wrap in the required {"rule": ...} envelope.
-	envelope := map[string]interface{}{"rule": ruleMap}
-	body, err := json.Marshal(envelope)
+// UpdateRuleRaw PUTs ruleJSON as the complete rule, wrapped in the required
+// {"rule": ...} envelope. Unlike UpdateRule it doesn't read the current rule or
+// touch the JSON, so ruleJSON must carry every field the API expects (see
+// GetRuleRaw) and no component ids that don't match the existing rule.
+func (c *Client) UpdateRuleRaw(uuid string, ruleJSON json.RawMessage) error {
+	body, err := json.Marshal(struct {
+		Rule json.RawMessage `json:"rule"`
+	}{ruleJSON})
 	if err != nil {
 		return fmt.Errorf("marshaling update rule request: %w", err)
 	}
@@ -451,6 +975,91 @@ func (c *Client) ListLabels(projectID string) ([]Label, error) {
 	return labels, nil
 }
 
+// LabelID returns the ID of the named label in a project, or 0 if no such label exists.
+// Labels are fetched once per project and cached on the client, so repeated
+// lookups during a single apply don't each call ListLabels.
+func (c *Client) LabelID(projectID, name string) (int, error) {
+	labels, err := c.cachedLabels(projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, l := range labels {
+		if l.Name == name {
+			return l.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// cachedLabels returns a project's labels, listing them only on the first call.
+func (c *Client) cachedLabels(projectID string) ([]Label, error) {
+	if c.labels == nil {
+		return c.ListLabels(projectID)
+	}
+	p := c.labels.project(projectID)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.loaded {
+		return p.labels, nil
+	}
+	labels, err := c.ListLabels(projectID)
+	if err != nil {
+		return nil, err
+	}
+	p.labels, p.loaded = labels, true
+	return labels, nil
+}
+
+// CreateLabelRequest is the payload for POST .../rule-labels.
+type CreateLabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"` // Required by the API (e.g. "B300"); omitting it returns 500.
+}
+
+// CreateLabel creates a rule label in a project via the internal API and
+// adds it to the project's cached labels, if they were listed.
+func (c *Client) CreateLabel(projectID string, label CreateLabelRequest) (*Label, error) {
+	body, err := json.Marshal(label)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling create label request: %w", err)
+	}
+
+	url := c.internalBaseURL(projectID) + "/rule-labels"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building create label request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("creating label: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("create label returned %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var created Label
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("decoding created label: %w", err)
+	}
+
+	if c.labels != nil {
+		p := c.labels.project(projectID)
+		p.mu.Lock()
+		if p.loaded {
+			p.labels = append(slices.Clone(p.labels), created)
+		}
+		p.mu.Unlock()
+	}
+
+	return &created, nil
+}
+
 // AddLabelToRule associates a label with a rule via the internal API.
 func (c *Client) AddLabelToRule(projectID, ruleUUID string, labelID int) error {
 	url := fmt.Sprintf("%s/rules/%s/labels/%d", c.internalBaseURL(projectID), ruleUUID, labelID)
@@ -473,16 +1082,61 @@ func (c *Client) AddLabelToRule(projectID, ruleUUID string, labelID int) error {
 	return nil
 }
 
+// RemoveLabelFromRule detaches a label from a rule via the internal API. The
+// label itself is left in the project.
+func (c *Client) RemoveLabelFromRule(projectID, ruleUUID string, labelID int) error {
+	url := fmt.Sprintf("%s/rules/%s/labels/%d", c.internalBaseURL(projectID), ruleUUID, labelID)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("building remove label request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("removing label from rule: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("remove label returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Scope is the resource a rule scope ARI points at: Kind "project" with the
+// project ID, "site" with the cloud ID, or whatever other resource type the
+// ARI names.
+type Scope struct {
+	Kind string
+	ID   string
+}
+
+// ParseScopeARI parses a scope ARI of the form
+// ari:cloud:{product}:{cloudId}:{kind}/{id}. The cloud ID segment may be empty,
+// as in site ARIs (ari:cloud:jira::site/{cloudId}).
+func ParseScopeARI(ari string) (Scope, error) {
+	parts := strings.SplitN(ari, ":", 5)
+	if len(parts) != 5 || parts[0] != "ari" || parts[1] == "" || parts[2] == "" {
+		return Scope{}, fmt.Errorf("malformed scope ARI %q: want ari:cloud:<product>:<cloudId>:<kind>/<id>", ari)
+	}
+	kind, id, ok := strings.Cut(parts[4], "/")
+	if !ok || kind == "" || id == "" {
+		return Scope{}, fmt.Errorf("malformed scope ARI %q: resource %q is not <kind>/<id>", ari, parts[4])
+	}
+	return Scope{Kind: kind, ID: id}, nil
+}
+
 // ExtractProjectID extracts the project ID from a scope ARI string.
 // Format: ari:cloud:jira:{cloudId}:project/{projectId}
-// Returns empty string if the ARI doesn't match the expected format.
+// Returns empty string for malformed ARIs and for other scope kinds such as site.
 func ExtractProjectID(ari string) string {
-	const prefix = ":project/"
-	idx := strings.LastIndex(ari, prefix)
-	if idx == -1 {
+	scope, err := ParseScopeARI(ari)
+	if err != nil || scope.Kind != "project" {
 		return ""
 	}
-	return ari[idx+len(prefix):]
+	return scope.ID
 }
 
 // stripComponentIDs recursively removes "id" fields from components so the API
@@ -510,6 +1164,35 @@ func stripComponentIDs(v interface{}) {
 	}
 }
 
+// copyComponentIDs sets the id of each component in to from the component at
+// the same position in from, as long as both have the same component and type.
+// Children and conditions are matched the same way; a mismatch leaves that
+// component and everything under it without ids, so the API creates them.
+func copyComponentIDs(from, to interface{}) {
+	src, ok := from.(map[string]interface{})
+	if !ok {
+		return
+	}
+	dst, ok := to.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if src["component"] != dst["component"] || src["type"] != dst["type"] {
+		return
+	}
+	if id, ok := src["id"]; ok {
+		dst["id"] = id
+	}
+
+	for _, key := range []string{"children", "conditions"} {
+		srcList, _ := src[key].([]interface{})
+		dstList, _ := dst[key].([]interface{})
+		for i := 0; i < len(dstList) && i < len(srcList); i++ {
+			copyComponentIDs(srcList[i], dstList[i])
+		}
+	}
+}
+
 // SetRuleState enables or disables a rule.
 func (c *Client) SetRuleState(uuid string, enabled bool) error {
 	stateVal := "DISABLED"
@@ -521,12 +1204,14 @@ func (c *Client) SetRuleState(uuid string, enabled bool) error {
 		return fmt.Errorf("marshaling set rule state request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPut, c.BaseURL+"/rule/"+uuid+"/state", bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("building set rule state request: %w", err)
-	}
-
-	resp, err := c.do(req)
+	// Setting the state is idempotent, so transient failures are retried.
+	resp, err := c.doWithRetry(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, c.BaseURL+"/rule/"+uuid+"/state", bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("building set rule state request: %w", err)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return fmt.Errorf("setting rule %s state: %w", uuid, err)
 	}
@@ -539,3 +1224,17 @@ func (c *Client) SetRuleState(uuid string, enabled bool) error {
 
 	return nil
 }
+
+// SetRulesState enables or disables each rule in uuids, e.g. to switch off every
+// rule with an incident label at once. It doesn't stop at the first failure:
+// every rule is attempted (each with SetRuleState's retries) and the returned
+// map holds the error for each rule that failed. An empty map means all succeeded.
+func (c *Client) SetRulesState(uuids []string, enabled bool) map[string]error {
+	errs := map[string]error{}
+	for _, uuid := range uuids {
+		if err := c.SetRuleState(uuid, enabled); err != nil {
+			errs[uuid] = err
+		}
+	}
+	return errs
+}
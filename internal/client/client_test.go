@@ -0,0 +1,1076 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient starts an httptest server that answers the bootstrap calls (tenant
+// info for New, /myself for CurrentAccountID) and delegates everything else to handler.
+// The returned client's BaseURL points at the server's /api path.
+func newTestClient(t *testing.T, handler http.HandlerFunc, aliases map[string]string) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"accountId":"acct-1"}`)
+	})
+	mux.HandleFunc("/", handler)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "user@test.com", "token", "", "", aliases)
+	if err != nil {
+		t.Fatalf("creating test client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+	return c
+}
+
+func TestNew_CopiesAliases(t *testing.T) {
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	c := newTestClient(t, http.NotFound, aliases)
+
+	// Mutating the caller's map must not leak into the client.
+	aliases["sprint"] = "customfield_10020"
+
+	if _, ok := c.FieldAliases["sprint"]; ok {
+		t.Error("client FieldAliases should not observe caller mutations")
+	}
+	if c.ReverseAliases["customfield_10709"] != "release_version" {
+		t.Errorf("ReverseAliases: got %q, want %q", c.ReverseAliases["customfield_10709"], "release_version")
+	}
+}
+
+func TestNew_HTMLTenantInfo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body>Sign in</body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	_, err := New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"HTML page instead of JSON", srv.URL + "/login", "SSO"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+// TestClient_ConcurrentUse exercises the client from many goroutines the way
+// Terraform does during a parallel apply. Run with -race to detect data races.
+func TestClient_ConcurrentUse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/rule/r1":
+			fmt.Fprint(w, `{"rule":{"uuid":"r1","name":"Rule","trigger":{},"components":[]}}`)
+		default:
+			fmt.Fprint(w, `[{"id":7,"name":"managed-by:terraform"}]`)
+		}
+	}, map[string]string{"release_version": "customfield_10709"})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetRule("r1"); err != nil {
+				errs <- err
+				return
+			}
+			id, err := c.LabelID("10001", "managed-by:terraform")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if id != 7 {
+				errs <- fmt.Errorf("label id: got %d, want 7", id)
+			}
+			if c.ReverseAliases[c.FieldAliases["release_version"]] != "release_version" {
+				errs <- fmt.Errorf("alias maps inconsistent")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestAutomationBaseURL(t *testing.T) {
+	want := "https://api.example.com/automation/public/jira/cloud-123/rest/v1"
+	for _, base := range []string{"https://api.example.com", "https://api.example.com/"} {
+		if got := AutomationBaseURL(base, "cloud-123"); got != want {
+			t.Errorf("%q: got %q, want %q", base, got, want)
+		}
+	}
+
+	if got := AutomationBaseURL(DefaultAPIBaseURL, "cloud-123"); got != "https://api.atlassian.com/automation/public/jira/cloud-123/rest/v1" {
+		t.Errorf("default: got %q", got)
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	c := newTestClient(t, http.NotFound, nil)
+	me, err := c.WhoAmI()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if me.AccountID != "acct-1" {
+		t.Errorf("WhoAmI AccountID: got %q, want %q", me.AccountID, "acct-1")
+	}
+
+	c.SiteURL = c.BaseURL // No /rest/api/3/myself handler there.
+	if _, err := c.WhoAmI(); err == nil {
+		t.Error("expected error for failing myself call")
+	}
+}
+
+func TestCurrentAccountID_Lazy(t *testing.T) {
+	var myself atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+		myself.Add(1)
+		fmt.Fprint(w, `{"accountId":"acct-1"}`)
+	})
+	mux.HandleFunc("/api/rule/", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"rule":{"uuid":"u-1","name":"rule","trigger":{}}}`)
+	})
+	mux.HandleFunc("/api/rule", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"uuid":"u-1"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+
+	// Reads don't need the account.
+	if _, err := c.GetRule("u-1"); err != nil {
+		t.Fatalf("get rule: %v", err)
+	}
+	if n := myself.Load(); n != 0 {
+		t.Errorf("myself calls before create: got %d, want 0", n)
+	}
+
+	// Creates look it up once, shared with copies made by WithContext.
+	for _, cc := range []*Client{c, c.WithContext(context.Background())} {
+		if _, err := cc.CreateRule(CreateRuleRequest{Name: "rule", Trigger: []byte(`{}`)}); err != nil {
+			t.Fatalf("create rule: %v", err)
+		}
+	}
+	if n := myself.Load(); n != 1 {
+		t.Errorf("myself calls after creates: got %d, want 1", n)
+	}
+	if id, err := c.CurrentAccountID(); err != nil || id != "acct-1" {
+		t.Errorf("CurrentAccountID: got %q, %v", id, err)
+	}
+
+	// A configured AccountID skips the lookup.
+	preset := &Client{AccountID: "acct-2"}
+	if id, err := preset.CurrentAccountID(); err != nil || id != "acct-2" {
+		t.Errorf("preset CurrentAccountID: got %q, %v", id, err)
+	}
+
+	// Without access to the current user, creates fail but reads still work.
+	denied := *c
+	denied.account = &accountCache{}
+	denied.SiteURL = srv.URL + "/api"
+	if _, err := denied.CreateRule(CreateRuleRequest{Name: "rule", Trigger: []byte(`{}`)}); err == nil {
+		t.Error("expected create to fail when the account can't be resolved")
+	}
+}
+
+func TestBulkCreateRules_SharesLabelLookup(t *testing.T) {
+	var created, listed, tagged atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/rule":
+			n := created.Add(1)
+			fmt.Fprintf(w, `{"ruleUuid":"rule-%d"}`, n)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/rule-labels"):
+			listed.Add(1)
+			fmt.Fprint(w, `[{"id":7,"name":"managed-by:terraform"}]`)
+		case r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/labels/7"):
+			tagged.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}, nil)
+
+	var rules []CreateRuleRequest
+	for i := 0; i < 10; i++ {
+		rules = append(rules, CreateRuleRequest{
+			Name:      fmt.Sprintf("rule %d", i),
+			ProjectID: "10001",
+			Trigger:   []byte(`{"component":"TRIGGER","type":"t"}`),
+		})
+	}
+
+	results := c.BulkCreateRules(rules, 4)
+
+	for i, res := range results {
+		if res.Err != nil {
+			t.Errorf("result %d: unexpected error: %v", i, res.Err)
+		}
+		if res.UUID == "" {
+			t.Errorf("result %d: empty UUID", i)
+		}
+		if res.Name != rules[i].Name {
+			t.Errorf("result %d: name got %q, want %q", i, res.Name, rules[i].Name)
+		}
+	}
+	if got := created.Load(); got != 10 {
+		t.Errorf("created: got %d, want 10", got)
+	}
+	if got := listed.Load(); got != 1 {
+		t.Errorf("ListLabels calls: got %d, want 1", got)
+	}
+	if got := tagged.Load(); got != 10 {
+		t.Errorf("AddLabelToRule calls: got %d, want 10", got)
+	}
+}
+
+func TestForEachRule(t *testing.T) {
+	var pages atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages.Add(1)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"data":[{"uuid":"r1"},{"uuid":"r2"}],"cursor":"next/page+1"}`)
+		case "next/page+1":
+			fmt.Fprint(w, `{"data":[{"uuid":"r3"}],"cursor":null}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}, nil)
+
+	rules, err := c.ListRules()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 3 || rules[2].UUID != "r3" {
+		t.Errorf("ListRules: got %+v, want r1, r2, r3", rules)
+	}
+
+	// Stopping early returns fn's error and fetches no further pages.
+	pages.Store(0)
+	errStop := errors.New("stop")
+	var seen []string
+	err = c.ForEachRule(func(rule RuleSummary) error {
+		seen = append(seen, rule.UUID)
+		if rule.UUID == "r2" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("error: got %v, want %v", err, errStop)
+	}
+	if fmt.Sprint(seen) != "[r1 r2]" || pages.Load() != 1 {
+		t.Errorf("seen %v over %d pages, want [r1 r2] over 1", seen, pages.Load())
+	}
+}
+
+func TestListRulesForProject(t *testing.T) {
+	var searches []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/rule/summary") {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		searches = append(searches, string(body))
+		if strings.Contains(string(body), `"cursor":"p2"`) {
+			fmt.Fprint(w, `{"data":[{"uuid":"r2","name":"Two","state":"DISABLED","enabled":false}],"cursor":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"uuid":"r1","name":"One","state":"ENABLED","enabled":true}],"cursor":"p2"}`)
+	}, nil)
+
+	rules, err := c.ListRulesForProject("10001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("rules: got %d, want 2", len(rules))
+	}
+	if rules[0].UUID != "r1" || !rules[0].Enabled {
+		t.Errorf("rules[0]: got %+v", rules[0])
+	}
+	if rules[1].Name != "Two" || rules[1].Enabled {
+		t.Errorf("rules[1]: got %+v", rules[1])
+	}
+	wantSearches := []string{
+		`{"scope":"ari:cloud:jira:cloud-123:project/10001","limit":100}`,
+		`{"cursor":"p2","scope":"ari:cloud:jira:cloud-123:project/10001","limit":100}`,
+	}
+	if !slices.Equal(searches, wantSearches) {
+		t.Errorf("search requests:\n got %v\nwant %v", searches, wantSearches)
+	}
+}
+
+func TestSearchRules(t *testing.T) {
+	// Full rules carry the update time the scoped search lacks; r3's is missing.
+	updated := map[string]string{"r1": "1743568964.174", "r2": "1700000000", "r3": "0"}
+	var mu sync.Mutex
+	var searches []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/rule/summary"):
+			fmt.Fprint(w, `{"data":[{"uuid":"r1","name":"Triage bugs","state":"ENABLED","labels":["managed-by:terraform"],"updated":1743568964.174},`+
+				`{"uuid":"r2","name":"Close stale","state":"DISABLED","labels":["other"],"updated":1700000000},`+
+				`{"uuid":"r3","name":"Bug report","state":"ENABLED","updated":1743568964.174}],"cursor":null}`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rule/summary"):
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			searches = append(searches, string(body))
+			mu.Unlock()
+			switch {
+			case strings.Contains(string(body), `"state":"DISABLED","scope"`):
+				fmt.Fprint(w, `{"data":[{"uuid":"r2","name":"Close stale","state":"DISABLED"}],"cursor":null}`)
+			case strings.Contains(string(body), `"trigger":"jira.manual.trigger.issue","scope"`):
+				fmt.Fprint(w, `{"data":[{"uuid":"r1","name":"Triage bugs","state":"ENABLED"}],"cursor":null}`)
+			case strings.Contains(string(body), `"scope"`):
+				fmt.Fprint(w, `{"data":[{"uuid":"r1","name":"Triage bugs","state":"ENABLED"},{"uuid":"r2","name":"Close stale","state":"DISABLED"},{"uuid":"r3","name":"Bug report","state":"ENABLED"}],"cursor":null}`)
+			case strings.Contains(string(body), `"cursor":"p2"`):
+				fmt.Fprint(w, `{"data":[{"uuid":"r3","name":"Bug report","state":"ENABLED"}],"cursor":null}`)
+			default:
+				fmt.Fprint(w, `{"data":[{"uuid":"r1","name":"Triage bugs","state":"ENABLED"}],"cursor":"p2"}`)
+			}
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/rule/"):
+			uuid := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			mu.Lock()
+			u, ok := updated[uuid]
+			mu.Unlock()
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, `{"rule":{"uuid":%q,"name":%q,"updated":%s,"trigger":{},"components":[]}}`, uuid, uuid, u)
+		default:
+			http.NotFound(w, r)
+		}
+	}, nil)
+
+	uuids := func(rules []RuleSummary) []string {
+		var ids []string
+		for _, r := range rules {
+			ids = append(ids, r.UUID)
+		}
+		return ids
+	}
+	tests := []struct {
+		name   string
+		filter RuleFilter
+		want   []string
+	}{
+		{name: "all", want: []string{"r1", "r2", "r3"}},
+		{name: "name is a case-insensitive substring", filter: RuleFilter{Name: "BUG"}, want: []string{"r1", "r3"}},
+		{name: "label is matched on the summaries", filter: RuleFilter{Label: "managed-by:terraform"}, want: []string{"r1"}},
+		{name: "state and trigger are searched by the API", filter: RuleFilter{State: "ENABLED", Trigger: "jira.manual.trigger.issue"}, want: []string{"r1", "r3"}},
+		{name: "project and state are searched by the API", filter: RuleFilter{ProjectID: "10001", State: "DISABLED"}, want: []string{"r2"}},
+		{name: "project and trigger are searched by the API", filter: RuleFilter{ProjectID: "10001", Trigger: "jira.manual.trigger.issue"}, want: []string{"r1"}},
+		{name: "updated since", filter: RuleFilter{UpdatedSince: time.Unix(1743568964, 0)}, want: []string{"r1", "r3"}},
+		{name: "updated since, from full rules", filter: RuleFilter{ProjectID: "10001", UpdatedSince: time.Unix(1743568964, 0)}, want: []string{"r1", "r3"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := c.SearchRules(tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(uuids(got), tc.want) {
+				t.Errorf("got %v, want %v", uuids(got), tc.want)
+			}
+		})
+	}
+
+	wantSearches := []string{
+		`{"trigger":"jira.manual.trigger.issue","state":"ENABLED","limit":100}`,
+		`{"cursor":"p2","trigger":"jira.manual.trigger.issue","state":"ENABLED","limit":100}`,
+		`{"state":"DISABLED","scope":"ari:cloud:jira:cloud-123:project/10001","limit":100}`,
+		`{"trigger":"jira.manual.trigger.issue","scope":"ari:cloud:jira:cloud-123:project/10001","limit":100}`,
+		`{"scope":"ari:cloud:jira:cloud-123:project/10001","limit":100}`,
+	}
+	if !slices.Equal(searches, wantSearches) {
+		t.Errorf("search requests:\n got %v\nwant %v", searches, wantSearches)
+	}
+
+	mu.Lock()
+	delete(updated, "r1") // Reading r1 now fails.
+	mu.Unlock()
+	if _, err := c.SearchRules(RuleFilter{ProjectID: "10001", UpdatedSince: time.Unix(1, 0)}); err == nil || !strings.Contains(err.Error(), "r1") {
+		t.Errorf("error: got %v, want one naming the rule", err)
+	}
+}
+
+func TestCreateLabel_UpdatesLabelCache(t *testing.T) {
+	var listed atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/10001/rule-labels"):
+			listed.Add(1)
+			fmt.Fprint(w, `[{"id":7,"name":"a"}]`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/10001/rule-labels"):
+			fmt.Fprint(w, `{"id":8,"name":"b"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}, nil)
+
+	if id, err := c.LabelID("10001", "b"); err != nil || id != 0 {
+		t.Fatalf("before create: got %d, %v; want 0", id, err)
+	}
+	if _, err := c.CreateLabel("10001", CreateLabelRequest{Name: "b", Color: "B300"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if id, err := c.LabelID("10001", "b"); err != nil || id != 8 {
+		t.Errorf("after create: got %d, %v; want 8", id, err)
+	}
+	if got := listed.Load(); got != 1 {
+		t.Errorf("ListLabels calls: got %d, want 1", got)
+	}
+}
+
+func TestLabelID_ProjectsDontWaitOnEachOther(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/10001/rule-labels") {
+			<-release
+		}
+		fmt.Fprint(w, `[{"id":7,"name":"a"}]`)
+	}, nil)
+	defer close(release)
+
+	go c.LabelID("10001", "a")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if id, err := c.LabelID("10002", "a"); err != nil || id != 7 {
+			t.Errorf("other project: got %d, %v; want 7", id, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lookup in another project waited on a pending listing")
+	}
+}
+
+func TestRemoveLabelFromRule(t *testing.T) {
+	var removed atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || !strings.HasSuffix(r.URL.Path, "/pro/rest/10001/rules/r1/labels/7") {
+			http.NotFound(w, r)
+			return
+		}
+		removed.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}, nil)
+
+	if err := c.RemoveLabelFromRule("10001", "r1", 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := removed.Load(); got != 1 {
+		t.Errorf("DELETE calls: got %d, want 1", got)
+	}
+	if err := c.RemoveLabelFromRule("10001", "r1", 8); err == nil {
+		t.Error("expected an error for an unknown label")
+	}
+}
+
+func TestParseScopeARI(t *testing.T) {
+	tests := []struct {
+		ari       string
+		want      Scope
+		wantErr   bool
+		projectID string
+	}{
+		{ari: "ari:cloud:jira:cloud-123:project/10001", want: Scope{"project", "10001"}, projectID: "10001"},
+		{ari: "ari:cloud:jira::site/cloud-123", want: Scope{"site", "cloud-123"}},
+		{ari: "ari:cloud:jira-software:cloud-123:board/42", want: Scope{"board", "42"}},
+		{ari: "ari:cloud:jira:cloud-123:project/", wantErr: true},
+		{ari: "ari:cloud:jira:cloud-123:project", wantErr: true},
+		{ari: "project/10001", wantErr: true},
+		{ari: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseScopeARI(tt.ari)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: error %v, wantErr %v", tt.ari, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.ari, got, tt.want)
+		}
+		if pid := ExtractProjectID(tt.ari); pid != tt.projectID {
+			t.Errorf("%q: ExtractProjectID got %q, want %q", tt.ari, pid, tt.projectID)
+		}
+	}
+}
+
+func TestRule_IsSystemOwned(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"user rule", Rule{AuthorAccountID: "acct-1"}, false},
+		{"system author", Rule{AuthorAccountID: "app-1"}, true},
+		{"unknown author", Rule{}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.IsSystemOwned([]string{"app-1"}); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetRuleState_RetriesTransientFailures(t *testing.T) {
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = 500 * time.Millisecond })
+
+	tests := []struct {
+		name      string
+		failures  int32
+		status    int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "recovers after 5xx", failures: 2, status: http.StatusBadGateway, wantCalls: 3},
+		{name: "recovers after 429", failures: 1, status: http.StatusTooManyRequests, wantCalls: 2},
+		{name: "gives up after retryAttempts", failures: 5, status: http.StatusServiceUnavailable, wantCalls: retryAttempts, wantErr: true},
+		{name: "client errors are not retried", failures: 5, status: http.StatusBadRequest, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/api/rule/r1/state" {
+					http.NotFound(w, r)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"value":"ENABLED"}` {
+					t.Errorf("body on call %d: got %s", calls.Load()+1, body)
+				}
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}, nil)
+
+			err := c.SetRuleState("r1", true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls: got %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestHooks(t *testing.T) {
+	retryBackoff = time.Millisecond
+	t.Cleanup(func() { retryBackoff = 500 * time.Millisecond })
+
+	var failures atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if failures.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}, nil)
+
+	var requests []string
+	var statuses []int
+	c.RequestHook = func(req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+	}
+	c.ResponseHook = func(resp *http.Response, err error) {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		statuses = append(statuses, resp.StatusCode)
+	}
+
+	if err := c.SetRuleState("r1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Each retry is observed.
+	want := []string{"PUT /api/rule/r1/state", "PUT /api/rule/r1/state"}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("requests: got %v, want %v", requests, want)
+	}
+	if fmt.Sprint(statuses) != "[503 204]" {
+		t.Errorf("statuses: got %v, want [503 204]", statuses)
+	}
+
+	// Unset hooks are skipped.
+	c.RequestHook, c.ResponseHook = nil, nil
+	if err := c.SetRuleState("r1", false); err != nil {
+		t.Fatalf("unexpected error without hooks: %v", err)
+	}
+}
+
+func TestWithContext_StopsRetrying(t *testing.T) {
+	retryBackoff = time.Hour
+	t.Cleanup(func() { retryBackoff = 500 * time.Millisecond })
+
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.WithContext(ctx).SetRuleState("r1", true)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error: got %v, want deadline exceeded", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls: got %d, want 1", got)
+	}
+
+	// The original client is not bound to the context.
+	if c.requestContext().Err() != nil {
+		t.Error("WithContext modified the original client")
+	}
+}
+
+func TestSetRulesState_CollectsErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/api/rule/ok1/state", "/api/rule/ok2/state":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}, nil)
+
+	errs := c.SetRulesState([]string{"ok1", "bad", "ok2"}, false)
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls: got %d, want 3 (must not stop at the first failure)", got)
+	}
+	if len(errs) != 1 || errs["bad"] == nil {
+		t.Errorf("errors: got %v, want only bad", errs)
+	}
+}
+
+func TestCreateRule_State(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		var gotState string
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Rule struct {
+					State string `json:"state"`
+				} `json:"rule"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding body: %v", err)
+			}
+			gotState = body.Rule.State
+			fmt.Fprint(w, `{"uuid":"u-1"}`)
+		}, nil)
+
+		if _, err := c.CreateRule(CreateRuleRequest{Name: "rule", Trigger: []byte(`{}`), Enabled: enabled}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "DISABLED"
+		if enabled {
+			want = "ENABLED"
+		}
+		if gotState != want {
+			t.Errorf("enabled=%v: payload state got %q, want %q", enabled, gotState, want)
+		}
+	}
+}
+
+func TestCreateRule_Scope(t *testing.T) {
+	tests := []struct {
+		name string
+		req  CreateRuleRequest
+		want []string
+	}{
+		{"site", CreateRuleRequest{}, []string{"ari:cloud:jira::site/cloud-123"}},
+		{"project", CreateRuleRequest{ProjectID: "10001"}, []string{"ari:cloud:jira:cloud-123:project/10001"}},
+		{"verbatim", CreateRuleRequest{ScopeARIs: []string{"ari:cloud:jira-servicedesk:cloud-123:queue/7"}},
+			[]string{"ari:cloud:jira-servicedesk:cloud-123:queue/7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Rule struct {
+						RuleScopeARIs []string `json:"ruleScopeARIs"`
+					} `json:"rule"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+				got = body.Rule.RuleScopeARIs
+				fmt.Fprint(w, `{"uuid":"u-1"}`)
+			}, nil)
+
+			tt.req.Name, tt.req.Trigger = "rule", []byte(`{}`)
+			if _, err := c.CreateRule(tt.req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ruleScopeARIs: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateRule_Actor(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *RuleActor
+		want  RuleActor
+	}{
+		{"default", nil, RuleActor{Type: ActorAccountID, Actor: "acct-1"}},
+		{"account without id", &RuleActor{Type: ActorAccountID}, RuleActor{Type: ActorAccountID, Actor: "acct-1"}},
+		{"other account", &RuleActor{Type: ActorAccountID, Actor: "acct-2"}, RuleActor{Type: ActorAccountID, Actor: "acct-2"}},
+		{"event initiator", &RuleActor{Type: ActorEventInitiator}, RuleActor{Type: ActorEventInitiator}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RuleActor
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Rule struct {
+						Actor RuleActor `json:"actor"`
+					} `json:"rule"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding body: %v", err)
+				}
+				got = body.Rule.Actor
+				fmt.Fprint(w, `{"uuid":"u-1"}`)
+			}, nil)
+
+			if _, err := c.CreateRule(CreateRuleRequest{Name: "rule", Trigger: []byte(`{}`), Actor: tt.actor}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("actor: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateRule_Actor(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *RuleActor
+		want  map[string]interface{}
+	}{
+		{"nil keeps current", nil, map[string]interface{}{"type": "ACCOUNT_ID", "actor": "acct-9"}},
+		{"event initiator", &RuleActor{Type: ActorEventInitiator}, map[string]interface{}{"type": "EVENT_INITIATOR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					fmt.Fprint(w, `{"rule":{"uuid":"u-1","name":"old","actor":{"type":"ACCOUNT_ID","actor":"acct-9"},"trigger":{},"components":[]}}`)
+				case http.MethodPut:
+					var body struct {
+						Rule map[string]interface{} `json:"rule"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("decoding body: %v", err)
+					}
+					sent = body.Rule
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}, nil)
+
+			if _, err := c.UpdateRule("u-1", UpdateRuleRequest{Name: "new", Trigger: []byte(`{}`), Actor: tt.actor}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fmt.Sprint(sent["actor"]); got != fmt.Sprint(tt.want) {
+				t.Errorf("actor: got %s, want %s", got, fmt.Sprint(tt.want))
+			}
+		})
+	}
+}
+
+func TestUpdateRule_PreserveComponentIDs(t *testing.T) {
+	current := `{"rule":{"uuid":"u-1","name":"r",
+		"trigger":{"id":"t1","component":"TRIGGER","type":"jira.manual.trigger.issue"},
+		"components":[
+			{"id":"c1","component":"CONDITION","type":"jira.condition.container.block","conditions":[
+				{"id":"c2","component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[
+					{"id":"c3","component":"ACTION","type":"codebarrel.action.log"}]}]},
+			{"id":"c4","component":"ACTION","type":"codebarrel.action.log"}]}}`
+	update := UpdateRuleRequest{
+		Name:    "r",
+		Trigger: []byte(`{"component":"TRIGGER","type":"jira.manual.trigger.issue"}`),
+		Components: []json.RawMessage{
+			[]byte(`{"component":"CONDITION","type":"jira.condition.container.block","conditions":[
+				{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[
+					{"component":"ACTION","type":"jira.issue.comment"}]}]}`),
+			[]byte(`{"component":"ACTION","type":"codebarrel.action.log"}`),
+			[]byte(`{"component":"ACTION","type":"codebarrel.action.log"}`),
+		},
+	}
+
+	for _, preserve := range []bool{false, true} {
+		var sent string
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				fmt.Fprint(w, current)
+			case http.MethodPut:
+				body, _ := io.ReadAll(r.Body)
+				sent = string(body)
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}, nil)
+
+		update.PreserveComponentIDs = preserve
+		if _, err := c.UpdateRule("u-1", update); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, id := range []string{"t1", "c1", "c2", "c4"} {
+			if got := strings.Contains(sent, `"id":"`+id+`"`); got != preserve {
+				t.Errorf("preserve=%v: id %s sent %v", preserve, id, got)
+			}
+		}
+		// The changed action and the new one at the end get fresh ids.
+		if strings.Contains(sent, `"id":"c3"`) || strings.Count(sent, `"id":`) != map[bool]int{false: 0, true: 4}[preserve] {
+			t.Errorf("preserve=%v: unexpected ids in %s", preserve, sent)
+		}
+	}
+}
+
+func TestUpdateRule_ComponentIDSources(t *testing.T) {
+	current := `{"rule":{"uuid":"u-1","name":"r","trigger":{"component":"TRIGGER","type":"t"},"components":[
+		{"id":"c1","component":"ACTION","type":"codebarrel.action.log"},
+		{"id":"c2","component":"ACTION","type":"jira.issue.comment"}]}}`
+	var sent struct {
+		Rule struct {
+			Components []struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"components"`
+		} `json:"rule"`
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, current)
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}, nil)
+
+	// A new log inserted at the top; the existing two moved down.
+	update := UpdateRuleRequest{
+		Name:    "r",
+		Trigger: []byte(`{"component":"TRIGGER","type":"t"}`),
+		Components: []json.RawMessage{
+			[]byte(`{"component":"ACTION","type":"codebarrel.action.log"}`),
+			[]byte(`{"component":"ACTION","type":"codebarrel.action.log"}`),
+			[]byte(`{"component":"ACTION","type":"jira.issue.comment"}`),
+		},
+		PreserveComponentIDs: true,
+		ComponentIDSources:   []int{-1, 0, 1},
+	}
+	if _, err := c.UpdateRule("u-1", update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, comp := range sent.Rule.Components {
+		ids = append(ids, comp.ID)
+	}
+	if want := []string{"", "c1", "c2"}; !slices.Equal(ids, want) {
+		t.Errorf("ids: got %q, want %q", ids, want)
+	}
+}
+
+func TestUpdateRule_Description(t *testing.T) {
+	var sent map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, `{"rule":{"uuid":"u-1","name":"old","description":"old description","notifyOnError":"FIRSTERROR","trigger":{},"components":[]}}`)
+		case http.MethodPut:
+			var body struct {
+				Rule map[string]interface{} `json:"rule"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding body: %v", err)
+			}
+			sent = body.Rule
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}, nil)
+
+	// An empty description clears the one set in the UI.
+	rule, err := c.UpdateRule("u-1", UpdateRuleRequest{Name: "new", Trigger: []byte(`{}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, ok := sent["description"]; !ok || got != "" {
+		t.Errorf("description: got %v (present %v), want empty string", got, ok)
+	}
+	if sent["notifyOnError"] != "FIRSTERROR" {
+		t.Errorf("API-managed fields not preserved: %v", sent)
+	}
+	// The rule is returned as sent, so it needn't be read again.
+	if rule.UUID != "u-1" || rule.Name != "new" || rule.Description != "" {
+		t.Errorf("returned rule: got %+v", rule)
+	}
+}
+
+func TestUpdateRuleRaw(t *testing.T) {
+	var gets atomic.Int32
+	var sent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets.Add(1)
+			http.NotFound(w, r)
+		case http.MethodPut:
+			if r.URL.Path != "/api/rule/u-1" {
+				http.NotFound(w, r)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			sent = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}, nil)
+
+	rule := `{"name":"rule","trigger":{"id":"7","component":"TRIGGER"},"components":[]}`
+	if err := c.UpdateRuleRaw("u-1", json.RawMessage(rule)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"rule":` + rule + `}`; sent != want {
+		t.Errorf("body:\n got %s\nwant %s", sent, want)
+	}
+	if got := gets.Load(); got != 0 {
+		t.Errorf("GET calls: got %d, want 0", got)
+	}
+
+	if err := c.UpdateRuleRaw("u-1", json.RawMessage(`{"name":`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestCreateRuleRaw(t *testing.T) {
+	var sent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/rule" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		fmt.Fprint(w, `{"ruleUuid":"r-1"}`)
+	}, nil)
+
+	rule := `{"name":"rule","state":"DISABLED","trigger":{"component":"TRIGGER"},"components":[]}`
+	uuid, err := c.CreateRuleRaw(json.RawMessage(rule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uuid != "r-1" {
+		t.Errorf("uuid: got %q, want r-1", uuid)
+	}
+	// Nothing is injected: no actor, notifyOnError or scope.
+	if want := `{"rule":` + rule + `}`; sent != want {
+		t.Errorf("body:\n got %s\nwant %s", sent, want)
+	}
+}
+
+func TestCreateRule_ResponseUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "uuid only", body: `{"uuid":"u-1"}`, want: "u-1"},
+		{name: "ruleUuid only", body: `{"ruleUuid":"r-1"}`, want: "r-1"},
+		{name: "neither", body: `{"id":42}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/api/rule" {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprint(w, tt.body)
+			}, nil)
+
+			got, err := c.CreateRule(CreateRuleRequest{
+				Name:    "rule",
+				Trigger: []byte(`{"component":"TRIGGER","type":"t"}`),
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got uuid %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("uuid: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
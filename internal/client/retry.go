@@ -0,0 +1,45 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// retryAttempts is the total number of tries doWithRetry makes.
+const retryAttempts = 3
+
+// retryBackoff is the wait before the first retry; it doubles after each.
+// A variable so tests can shorten it.
+var retryBackoff = 500 * time.Millisecond
+
+// doWithRetry sends the request returned by build, retrying transport errors,
+// 429 and 5xx responses with exponential backoff. build is called per attempt
+// so request bodies are fresh. Only use it for idempotent requests. The last
+// response or error is returned as-is for the caller to handle; waiting stops
+// early when the client's context is done.
+func (c *Client) doWithRetry(build func() (*http.Request, error)) (*http.Response, error) {
+	wait := retryBackoff
+	for attempt := 1; ; attempt++ {
+		req, err := build()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req)
+		if attempt == retryAttempts || (err == nil && !retryableStatus(resp.StatusCode)) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(wait):
+		case <-c.requestContext().Done():
+			return nil, c.requestContext().Err()
+		}
+		wait *= 2
+	}
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
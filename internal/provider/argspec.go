@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ArgSpec describes one arg accepted by a trigger or component type, so
+// tooling can generate forms and the provider can validate args uniformly.
+type ArgSpec struct {
+	Name        string
+	Required    bool
+	Description string
+}
+
+// matchTypeArg is the match_type arg shared by the condition components.
+var matchTypeArg = ArgSpec{Name: "match_type", Description: "The IF block's condition match type: all (the default) or any. Both behave the same with a single condition; setting it keeps a rule switched to any in Jira unchanged when re-applied."}
+
+// conditionArgs are the args of the special-cased condition component.
+// first and operator are required unless condition_json is set instead.
+var conditionArgs = []ArgSpec{
+	{Name: "first", Description: "Left-hand value, usually a smart value such as {{issue.status.name}}. Required unless condition_json is set."},
+	{Name: "operator", Description: "Comparison operator (e.g. EQUALS, NOT_EQUALS, CONTAINS). Required unless condition_json is set."},
+	{Name: "second", Description: "Right-hand value."},
+	{Name: "condition_json", Description: "A CONDITION component's API JSON object, used verbatim as the IF block's condition in place of the comparator args."},
+	matchTypeArg,
+}
+
+// userConditionArgs are the args of the special-cased user_condition component.
+var userConditionArgs = []ArgSpec{
+	{Name: "check", Required: true, Description: "One of user_is, user_is_not, in_group, not_in_group."},
+	{Name: "value", Required: true, Description: "The user or group the check compares against."},
+	{Name: "user", Description: "User field to check (e.g. reporter, assignee). Defaults to initiator, the user who triggered the rule."},
+	matchTypeArg,
+}
+
+// branchArgs are the args of the special-cased branch component.
+var branchArgs = []ArgSpec{
+	{Name: "json", Required: true, Description: "The branch component's API JSON object without children, e.g. from jsonencode(); the then actions become its children."},
+}
+
+// TriggerArgSpecs returns the args of a trigger type, or false if the type is unknown.
+func TriggerArgSpecs(triggerType string) ([]ArgSpec, bool) {
+	def, ok := triggerRegistry[triggerType]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(def.args), true
+}
+
+// ComponentArgSpecs returns the args of a component type (including
+// "condition", "user_condition" and "branch"), or false if the type is unknown.
+func ComponentArgSpecs(componentType string) ([]ArgSpec, bool) {
+	switch componentType {
+	case "condition":
+		return slices.Clone(conditionArgs), true
+	case "user_condition":
+		return slices.Clone(userConditionArgs), true
+	case branchComponentType:
+		return slices.Clone(branchArgs), true
+	}
+	def, ok := componentRegistry[componentType]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(def.args), true
+}
+
+// validateArgs rejects args not in specs and required args that are missing
+// or empty. Type-specific checks (enums, numbers) stay in the builders.
+func validateArgs(typeName string, specs []ArgSpec, args map[string]string) error {
+	var unknown []string
+	for name := range args {
+		if !slices.ContainsFunc(specs, func(s ArgSpec) bool { return s.Name == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s does not accept arg(s) %q; valid args: %s", typeName, unknown, argNames(specs))
+	}
+	for _, s := range specs {
+		if s.Required && args[s.Name] == "" {
+			return fmt.Errorf("%s requires a '%s' arg", typeName, s.Name)
+		}
+	}
+	return nil
+}
+
+func argNames(specs []ArgSpec) []string {
+	names := make([]string, len(specs))
+	for i, s := range specs {
+		names[i] = s.Name
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+// validComponentArgs and validTriggerArgs are minimal valid args for every type; each must set exactly the
+// required args so the spec test below can drop them one by one.
+var validComponentArgs = map[string]map[string]string{
+	"log":                      {"message": "hi"},
+	"comment":                  {"message": "hi"},
+	"assign_issue":             {},
+	"set_property":             {"key": "k"},
+	"add_release_related_work": {"version_field": "fixVersions", "category": "Docs", "title": "T", "url": "https://example.com"},
+	"raw":                      {"json": `{"component":"ACTION","type":"jira.issue.assign"}`},
+}
+
+var validTriggerArgs = map[string]map[string]string{
+	"status_transition": {"from_status": "To Do", "to_status": "Done"},
+	"scheduled":         {"cron": "0 0 9 * * ?"},
+	"manual":            {},
+	"incoming_webhook":  {},
+}
+
+func TestComponentArgSpecs_MatchBuilders(t *testing.T) {
+	for name := range componentRegistry {
+		args, ok := validComponentArgs[name]
+		if !ok {
+			t.Errorf("%s: no validComponentArgs entry", name)
+			continue
+		}
+		specs, ok := ComponentArgSpecs(name)
+		if !ok {
+			t.Fatalf("%s: no specs", name)
+		}
+		if _, err := buildActionWithDebug(name, args, "cloud-123", "u", "t", ""); err != nil {
+			t.Errorf("%s: valid args rejected: %v", name, err)
+		}
+		for _, s := range specs {
+			if _, set := args[s.Name]; set != s.Required {
+				t.Errorf("%s: validComponentArgs[%q] set=%v, spec required=%v", name, s.Name, set, s.Required)
+			}
+			if !s.Required {
+				continue
+			}
+			missing := maps.Clone(args)
+			delete(missing, s.Name)
+			if _, err := buildActionWithDebug(name, missing, "cloud-123", "u", "t", ""); err == nil {
+				t.Errorf("%s: expected error without required %q", name, s.Name)
+			}
+		}
+	}
+}
+
+func TestTriggerArgSpecs_MatchBuilders(t *testing.T) {
+	for name := range triggerRegistry {
+		args, ok := validTriggerArgs[name]
+		if !ok {
+			t.Errorf("%s: no validTriggerArgs entry", name)
+			continue
+		}
+		specs, ok := TriggerArgSpecs(name)
+		if !ok {
+			t.Fatalf("%s: no specs", name)
+		}
+		if _, err := BuildTriggerJSON(name, args, "cloud-123", "10001"); err != nil {
+			t.Errorf("%s: valid args rejected: %v", name, err)
+		}
+		for _, s := range specs {
+			if !s.Required {
+				continue
+			}
+			missing := maps.Clone(args)
+			delete(missing, s.Name)
+			if _, err := BuildTriggerJSON(name, missing, "cloud-123", "10001"); err == nil {
+				t.Errorf("%s: expected error without required %q", name, s.Name)
+			}
+		}
+	}
+}
+
+func TestValidateArgs_UnknownArg(t *testing.T) {
+	_, err := BuildTriggerJSON("scheduled", map[string]string{"cron": "0 0 9 * * ?", "jqll": "project = X"}, "cloud-123", "10001")
+	if err == nil || !strings.Contains(err.Error(), "jqll") {
+		t.Errorf("expected unknown arg error naming jqll, got %v", err)
+	}
+
+	_, err = buildActionWithDebug("log", map[string]string{"message": "hi", "lvl": "info"}, "cloud-123", "u", "t", "")
+	if err == nil || !strings.Contains(err.Error(), "lvl") {
+		t.Errorf("expected unknown arg error naming lvl, got %v", err)
+	}
+}
+
+func TestArgSpecs_Accessors(t *testing.T) {
+	specs, ok := ComponentArgSpecs("condition")
+	if !ok || len(specs) != len(conditionArgs) {
+		t.Errorf("condition specs: got %v, %v", specs, ok)
+	}
+	specs[0].Name = "mutated"
+	if conditionArgs[0].Name != "first" {
+		t.Error("ComponentArgSpecs must return a copy")
+	}
+
+	if _, ok := ComponentArgSpecs("nope"); ok {
+		t.Error("expected false for unknown component type")
+	}
+	if _, ok := TriggerArgSpecs("nope"); ok {
+		t.Error("expected false for unknown trigger type")
+	}
+	for _, name := range SupportedComponentTypes() {
+		specs, _ := ComponentArgSpecs(name)
+		for _, s := range specs {
+			if s.Description == "" {
+				t.Errorf("%s.%s: empty description", name, s.Name)
+			}
+		}
+	}
+}
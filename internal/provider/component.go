@@ -1,21 +1,29 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"regexp"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 
+	"terraform-provider-jira-automation/internal/client"
+
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // componentModel is the Terraform model for the "components" block.
 type componentModel struct {
+	Key  types.String       `tfsdk:"key"`
 	Type types.String       `tfsdk:"type"`
 	Args types.Map          `tfsdk:"args"`
+	When types.Map          `tfsdk:"when"`
 	Then []innerActionModel `tfsdk:"then"`
 	Else []innerActionModel `tfsdk:"else"`
 }
@@ -24,6 +32,7 @@ type componentModel struct {
 type innerActionModel struct {
 	Type types.String `tfsdk:"type"`
 	Args types.Map    `tfsdk:"args"`
+	When types.Map    `tfsdk:"when"`
 }
 
 // componentBuilder builds the API JSON for a single action from user args.
@@ -34,38 +43,120 @@ type componentParser func(raw json.RawMessage) (map[string]string, error)
 
 type componentDef struct {
 	apiType string
+	args    []ArgSpec
 	build   componentBuilder
 	parse   componentParser
 }
 
-// debugLogPrefix is the prefix used by auto-generated debug log actions.
-const debugLogPrefix = "[DEBUG add_release_related_work] "
+// debugLogCount is the number of log actions buildDebugLogs prepends.
+const debugLogCount = 4
 
 // componentRegistry maps user-facing type names to their builder/parser pairs.
-// "condition" is special-cased and not in this registry.
+// "condition", "user_condition" and "branch" are special-cased and not in this registry.
 var componentRegistry = map[string]componentDef{
 	"log": {
 		apiType: "codebarrel.action.log",
-		build:   buildLog,
-		parse:   parseLog,
+		args: []ArgSpec{
+			{Name: "message", Required: true, Description: "Message written to the audit log."},
+			{Name: "level", Description: "One of debug, info, warn, error; written as a [LEVEL] prefix."},
+		},
+		build: buildLog,
+		parse: parseLog,
 	},
 	"comment": {
 		apiType: "jira.issue.comment",
-		build:   buildComment,
-		parse:   parseComment,
+		args: []ArgSpec{
+			{Name: "message", Required: true, Description: "Comment body: wiki markup text, or an ADF document as JSON with body_format = adf."},
+			{Name: "body_format", Description: "wiki (default) or adf."},
+			{Name: "visibility_type", Description: "ROLE or GROUP to restrict who can see the comment; requires visibility_value."},
+			{Name: "visibility_value", Description: "Name of the project role or group that can see the comment."},
+		},
+		build: buildComment,
+		parse: parseComment,
 	},
 	"add_release_related_work": {
 		apiType: "jira.issue.outgoing.webhook",
-		build:   buildAddReleaseRelatedWork,
-		parse:   parseAddReleaseRelatedWork,
+		args: []ArgSpec{
+			{Name: "version_field", Required: true, Description: "Field (or alias) holding the fix version."},
+			{Name: "category", Required: true, Description: "Related work category."},
+			{Name: "title", Required: true, Description: "Related work title."},
+			{Name: "url", Required: true, Description: "Related work URL."},
+			{Name: "content_type", Description: "custom (default) or application/json."},
+			{Name: "continue_on_error", Description: "\"true\" to continue the rule when the request fails."},
+			{Name: "response_enabled", Description: "\"true\" to wait for the response and expose it to later actions."},
+			{Name: "debug", Description: "\"true\" to add log actions dumping the request."},
+		},
+		build: buildAddReleaseRelatedWork,
+		parse: parseAddReleaseRelatedWork,
+	},
+	"assign_issue": {
+		apiType: "jira.issue.assign",
+		args: []ArgSpec{
+			{Name: "assignee", Description: "Account ID or smart value (e.g. {{reporter.accountId}}) of the new assignee. Omit it to unassign the issue."},
+		},
+		build: buildAssignIssue,
+		parse: parseAssignIssue,
+	},
+	"set_property": {
+		apiType: "jira.set.entity.property",
+		args: []ArgSpec{
+			{Name: "key", Required: true, Description: "Issue entity property key."},
+			{Name: "value", Description: "Property value as a string, passed through as-is."},
+			{Name: "value_json", Description: "Property value as JSON, e.g. from jsonencode(), for numbers, booleans, objects and arrays. Conflicts with value."},
+		},
+		build: buildSetProperty,
+		parse: parseSetProperty,
 	},
+	// raw has no API type of its own: it carries any component verbatim, and
+	// the parsers fall back to it for API types this registry doesn't know.
+	rawComponentType: {
+		args: []ArgSpec{
+			{Name: "json", Required: true, Description: "The component's API JSON object, e.g. from jsonencode(); needs component and type keys."},
+		},
+		build: buildRaw,
+		parse: parseRaw,
+	},
+}
+
+// rawComponentType is the user-facing type of verbatim JSON components.
+const rawComponentType = "raw"
+
+// errUnsupportedShape is returned (wrapped) by a parser whose API type it
+// knows but whose value has a shape it can't interpret, e.g. from another
+// schemaVersion. The component is then read as a raw component.
+var errUnsupportedShape = errors.New("unsupported value shape")
+
+// parseAs runs userType's parser on raw, falling back to a raw component when
+// the parser reports errUnsupportedShape. It returns the type actually used.
+func parseAs(userType string, raw json.RawMessage) (string, map[string]string, error) {
+	args, err := componentRegistry[userType].parse(raw)
+	if errors.Is(err, errUnsupportedShape) {
+		userType = rawComponentType
+		args, err = parseRaw(raw)
+	}
+	return userType, args, err
+}
+
+// branchComponentType is the user-facing type of BRANCH components (related
+// issues, JQL, ...), which run their then actions once per branched issue.
+const branchComponentType = "branch"
+
+// SupportedComponentTypes returns the user-facing component types, sorted.
+// It includes "condition", "user_condition" and "branch", which are handled
+// outside componentRegistry.
+func SupportedComponentTypes() []string {
+	names := append(slices.Collect(maps.Keys(componentRegistry)), "condition", "user_condition", branchComponentType)
+	slices.Sort(names)
+	return names
 }
 
 // apiTypeToComponentUserType maps API types back to user-facing names.
 var apiTypeToComponentUserType = func() map[string]string {
 	m := make(map[string]string, len(componentRegistry))
 	for userType, def := range componentRegistry {
-		m[def.apiType] = userType
+		if def.apiType != "" {
+			m[def.apiType] = userType
+		}
 	}
 	return m
 }()
@@ -79,21 +170,9 @@ func resolveAliases(args map[string]string, aliases map[string]string) map[strin
 	if len(aliases) == 0 {
 		return args
 	}
-	// Sort aliases by length descending to avoid partial matches.
-	sorted := sortedKeys(aliases)
 	result := make(map[string]string, len(args))
 	for k, v := range args {
-		// Smart value replacement in any string value.
-		resolved := v
-		for _, alias := range sorted {
-			fieldID := aliases[alias]
-			resolved = replaceSmartValueField(resolved, alias, fieldID)
-		}
-		// Bare value: if the entire resolved value is an alias, replace it.
-		if id, ok := aliases[resolved]; ok {
-			resolved = id
-		}
-		result[k] = resolved
+		result[k] = replaceAliases(v, aliases)
 	}
 	return result
 }
@@ -103,54 +182,145 @@ func unresolveAliases(args map[string]string, reverse map[string]string) map[str
 	if len(reverse) == 0 {
 		return args
 	}
-	sorted := sortedKeys(reverse)
 	result := make(map[string]string, len(args))
 	for k, v := range args {
-		resolved := v
-		for _, fieldID := range sorted {
-			alias := reverse[fieldID]
-			resolved = replaceSmartValueField(resolved, fieldID, alias)
-		}
-		if alias, ok := reverse[resolved]; ok {
-			resolved = alias
-		}
-		result[k] = resolved
+		result[k] = replaceAliases(v, reverse)
 	}
 	return result
 }
 
-// replaceSmartValueField replaces {{issue.OLD...}} and {{triggerIssue.OLD...}} with the new field name.
-func replaceSmartValueField(s, oldField, newField string) string {
-	// Match {{issue.OLD}} {{issue.OLD.xxx}} {{issue.OLD}} etc.
-	// We look for the pattern and replace just the field name part.
-	old1 := "{{issue." + oldField
-	new1 := "{{issue." + newField
-	old2 := "{{triggerIssue." + oldField
-	new2 := "{{triggerIssue." + newField
-	s = strings.ReplaceAll(s, old1, new1)
-	s = strings.ReplaceAll(s, old2, new2)
-	return s
+// smartValueFieldPattern matches the field path that starts an issue or
+// trigger issue smart value: customfield_10020.name in
+// {{issue.customfield_10020.name.abbreviate(10)}}.
+var smartValueFieldPattern = regexp.MustCompile(`\{\{(?:issue|triggerIssue)\.([A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]+)*)`)
+
+// replaceAliases maps field names in v from the keys of m to its values, both
+// when v is exactly a key and inside smart values. In a smart value the
+// longest run of leading path segments that is a key is replaced, so method
+// chains such as {{issue.OLD.format("###")}} keep their tail verbatim while
+// {{issue.OLDER}} is left alone. Each smart value is looked up once, so the
+// result doesn't depend on the order of m.
+func replaceAliases(v string, m map[string]string) string {
+	if to, ok := m[v]; ok {
+		return to
+	}
+	locs := smartValueFieldPattern.FindAllStringSubmatchIndex(v, -1)
+	if len(locs) == 0 {
+		return v
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		start, end := loc[2], loc[3]
+		b.WriteString(v[last:start])
+		last = start
+		for field := v[start:end]; ; {
+			if to, ok := m[field]; ok {
+				b.WriteString(to)
+				last = start + len(field)
+				break
+			}
+			i := strings.LastIndexByte(field, '.')
+			if i < 0 {
+				break
+			}
+			field = field[:i]
+		}
+	}
+	b.WriteString(v[last:])
+	return b.String()
+}
+
+// resolveAliasesInJSON applies resolveAliases to every string value (not
+// object key) in raw. Used for trigger_json/components_json when
+// resolve_aliases_in_json is enabled.
+func resolveAliasesInJSON(raw json.RawMessage, aliases map[string]string) (json.RawMessage, error) {
+	if len(aliases) == 0 {
+		return raw, nil
+	}
+	return mapJSONStrings(raw, func(s string) string { return replaceAliases(s, aliases) })
+}
+
+// unresolveAliasesInJSON is the reverse of resolveAliasesInJSON.
+func unresolveAliasesInJSON(raw json.RawMessage, reverse map[string]string) (json.RawMessage, error) {
+	if len(reverse) == 0 {
+		return raw, nil
+	}
+	return mapJSONStrings(raw, func(s string) string { return replaceAliases(s, reverse) })
+}
+
+// mapJSONStrings decodes raw, applies fn to every string value and re-encodes
+// it. Numbers are kept verbatim; object keys come back sorted.
+func mapJSONStrings(raw json.RawMessage, fn func(string) string) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch t := v.(type) {
+		case string:
+			return fn(t)
+		case map[string]interface{}:
+			for k, e := range t {
+				t[k] = walk(e)
+			}
+		case []interface{}:
+			for i, e := range t {
+				t[i] = walk(e)
+			}
+		}
+		return v
+	}
+	return json.Marshal(walk(v))
 }
 
-// sortedKeys returns map keys sorted by length descending (longest first).
-func sortedKeys(m map[string]string) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+// unbalancedSmartValue describes the first unbalanced {{ or }} in s, or
+// returns "" if every {{ is closed by a later }}.
+func unbalancedSmartValue(s string) string {
+	var open []int // Offsets of unclosed {{.
+	for i := 0; i+1 < len(s); i++ {
+		switch s[i : i+2] {
+		case "{{":
+			open = append(open, i)
+			i++
+		case "}}":
+			if len(open) == 0 {
+				return fmt.Sprintf("}} at offset %d has no matching {{", i)
+			}
+			open = open[:len(open)-1]
+			i++
+		}
 	}
-	sort.Slice(keys, func(i, j int) bool {
-		return len(keys[i]) > len(keys[j])
-	})
-	return keys
+	if len(open) > 0 {
+		return fmt.Sprintf("{{ at offset %d is never closed with }}", open[0])
+	}
+	return ""
 }
 
 // --- Builders ---
 
+// logLevels are the accepted values of the log action's optional level arg.
+// The API has no level field, so the level is written as a "[LEVEL] " prefix.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// logLevelPattern matches the level prefix written by buildLog. It can't match
+// client.DefaultDebugLogPrefix, so user debug logs never look like generated ones.
+var logLevelPattern = regexp.MustCompile(`^\[(DEBUG|INFO|WARN|ERROR)\] `)
+
 func buildLog(args map[string]string, _, _, _ string) (json.RawMessage, error) {
 	msg := args["message"]
 	if msg == "" {
 		return nil, fmt.Errorf("log requires a 'message' arg")
 	}
+	if level := args["level"]; level != "" {
+		if !slices.Contains(logLevels, level) {
+			return nil, fmt.Errorf("log level must be one of %s, got %q", strings.Join(logLevels, ", "), level)
+		}
+		msg = "[" + strings.ToUpper(level) + "] " + msg
+	}
 	action := map[string]interface{}{
 		"children":      []interface{}{},
 		"component":     "ACTION",
@@ -168,6 +338,25 @@ func buildComment(args map[string]string, _, _, _ string) (json.RawMessage, erro
 	if msg == "" {
 		return nil, fmt.Errorf("comment requires a 'message' arg")
 	}
+	var comment interface{} = msg
+	switch format := args["body_format"]; format {
+	case "", "wiki":
+	case "adf":
+		doc, err := adfDocument(msg)
+		if err != nil {
+			return nil, err
+		}
+		comment = doc
+	default:
+		return nil, fmt.Errorf("comment body_format must be one of wiki, adf, got %q", format)
+	}
+	if err := commentVisibilityError(args); err != nil {
+		return nil, err
+	}
+	var visibility interface{}
+	if t := args["visibility_type"]; t != "" {
+		visibility = map[string]string{"type": t, "value": args["visibility_value"]}
+	}
 	action := map[string]interface{}{
 		"children":      []interface{}{},
 		"component":     "ACTION",
@@ -176,9 +365,9 @@ func buildComment(args map[string]string, _, _, _ string) (json.RawMessage, erro
 		"schemaVersion": 2,
 		"type":          "jira.issue.comment",
 		"value": map[string]interface{}{
-			"comment":           msg,
+			"comment":           comment,
 			"publicComment":     false,
-			"commentVisibility": nil,
+			"commentVisibility": visibility,
 			"sendNotifications": true,
 			"addCommentOnce":    false,
 		},
@@ -186,6 +375,100 @@ func buildComment(args map[string]string, _, _, _ string) (json.RawMessage, erro
 	return json.Marshal(action)
 }
 
+// commentVisibilityError checks a comment's visibility args. Without a value
+// the API drops the restriction and posts a public comment, so a type alone
+// is an error rather than a no-op.
+func commentVisibilityError(args map[string]string) error {
+	t, v := args["visibility_type"], args["visibility_value"]
+	switch {
+	case t == "" && v == "":
+		return nil
+	case t == "":
+		return fmt.Errorf("comment visibility_value requires visibility_type (ROLE or GROUP)")
+	case t != "ROLE" && t != "GROUP":
+		return fmt.Errorf("comment visibility_type must be one of ROLE, GROUP, got %q", t)
+	case v == "":
+		return fmt.Errorf("comment visibility_type = %s requires a visibility_value; without one the comment is public", t)
+	}
+	return nil
+}
+
+// buildAssignIssue assigns the issue to a user, or unassigns it when no
+// assignee is given. Assignees with smart values are resolved at run time.
+func buildAssignIssue(args map[string]string, _, _, _ string) (json.RawMessage, error) {
+	value := map[string]interface{}{"assignType": "UNASSIGN"}
+	if assignee := args["assignee"]; assignee != "" {
+		ref := nameRef{Type: "ID", Value: assignee}
+		if strings.Contains(assignee, "{{") {
+			ref.Type = "SMART"
+		}
+		value = map[string]interface{}{"assignType": "SPECIFY_USER", "assignee": ref}
+	}
+	action := map[string]interface{}{
+		"children":      []interface{}{},
+		"component":     "ACTION",
+		"conditions":    []interface{}{},
+		"connectionId":  nil,
+		"schemaVersion": 5,
+		"type":          "jira.issue.assign",
+		"value":         value,
+	}
+	return json.Marshal(action)
+}
+
+// buildSetProperty sets an issue entity property. value is passed through as
+// an opaque string so smart values survive the round-trip; value_json is sent
+// as the JSON value it holds, since the API accepts any JSON type.
+func buildSetProperty(args map[string]string, _, _, _ string) (json.RawMessage, error) {
+	key := args["key"]
+	if key == "" {
+		return nil, fmt.Errorf("set_property requires a 'key' arg")
+	}
+	var value interface{} = args["value"]
+	if v, ok := args["value_json"]; ok {
+		if _, ok := args["value"]; ok {
+			return nil, fmt.Errorf("set_property takes 'value' or 'value_json', not both")
+		}
+		if !json.Valid([]byte(v)) {
+			return nil, fmt.Errorf("set_property value_json must be valid JSON, got %q", v)
+		}
+		value = json.RawMessage(v)
+	}
+	action := map[string]interface{}{
+		"children":      []interface{}{},
+		"component":     "ACTION",
+		"conditions":    []interface{}{},
+		"connectionId":  nil,
+		"schemaVersion": 3,
+		"type":          "jira.set.entity.property",
+		"value": map[string]interface{}{
+			"entityType": "ISSUE",
+			"entity":     nil,
+			"key":        key,
+			"value":      value,
+		},
+	}
+	return json.Marshal(action)
+}
+
+// buildRaw passes a component's JSON through as-is after checking its shape.
+func buildRaw(args map[string]string, _, _, _ string) (json.RawMessage, error) {
+	var comp map[string]interface{}
+	if err := json.Unmarshal([]byte(args["json"]), &comp); err != nil {
+		return nil, fmt.Errorf("raw json must be a JSON object: %w", err)
+	}
+	for _, key := range []string{"component", "type"} {
+		if s, _ := comp[key].(string); s == "" {
+			return nil, fmt.Errorf("raw json must have a non-empty %q key", key)
+		}
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(args["json"])); err != nil {
+		return nil, fmt.Errorf("raw json: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, webhookToken string) (json.RawMessage, error) {
 	versionField := args["version_field"]
 	category := args["category"]
@@ -194,6 +477,18 @@ func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, we
 	if versionField == "" || category == "" || title == "" || url == "" {
 		return nil, fmt.Errorf("add_release_related_work requires version_field, category, title, and url args")
 	}
+	contentType, err := webhookContentType(args)
+	if err != nil {
+		return nil, err
+	}
+	continueOnError, err := boolArg(args, "continue_on_error")
+	if err != nil {
+		return nil, err
+	}
+	responseEnabled, err := boolArg(args, "response_enabled")
+	if err != nil {
+		return nil, err
+	}
 	if webhookUser == "" || webhookToken == "" {
 		return nil, fmt.Errorf("add_release_related_work requires webhook_user and webhook_token in provider config (or JIRA_WEBHOOK_USER / JIRA_WEBHOOK_TOKEN env vars)")
 	}
@@ -203,7 +498,7 @@ func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, we
 		cloudID, versionField,
 	)
 
-	authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(webhookUser+":"+webhookToken))
+	authHeader := relatedWorkAuthHeader(webhookUser, webhookToken)
 
 	customBody := map[string]string{
 		"category": category,
@@ -223,8 +518,8 @@ func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, we
 		"schemaVersion": 1,
 		"type":          "jira.issue.outgoing.webhook",
 		"value": map[string]interface{}{
-			"contentType":            "custom",
-			"continueOnErrorEnabled": false,
+			"contentType":            contentType,
+			"continueOnErrorEnabled": continueOnError,
 			"customBody":             string(customBodyJSON),
 			"headers": []map[string]interface{}{
 				{
@@ -235,7 +530,7 @@ func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, we
 				},
 			},
 			"method":          "POST",
-			"responseEnabled": false,
+			"responseEnabled": responseEnabled,
 			"sendIssue":       false,
 			"url":             webhookURL,
 		},
@@ -243,33 +538,98 @@ func buildAddReleaseRelatedWork(args map[string]string, cloudID, webhookUser, we
 	return json.Marshal(action)
 }
 
-// buildDebugLogs returns 4 log actions that dump useful runtime info for add_release_related_work.
-func buildDebugLogs(args map[string]string, cloudID string) ([]json.RawMessage, error) {
-	versionField := args["version_field"]
-	category := args["category"]
-	title := args["title"]
-	url := args["url"]
+// webhookContentTypes are the values the API accepts for a webhook's contentType.
+var webhookContentTypes = []string{"custom", "application/json"}
 
-	webhookURL := fmt.Sprintf(
-		"https://api.atlassian.com/ex/jira/%s/rest/api/3/version/{{issue.%s.format(\"###\")}}/relatedwork",
-		cloudID, versionField,
-	)
+// webhookContentType returns the content_type arg, defaulting to "custom"
+// so existing rules keep their current body handling. The parser leaves
+// "custom" out; preserveDefaultArgs keeps it when a config sets it.
+func webhookContentType(args map[string]string) (string, error) {
+	ct := args["content_type"]
+	if ct == "" {
+		return "custom", nil
+	}
+	if !slices.Contains(webhookContentTypes, ct) {
+		return "", fmt.Errorf("content_type must be one of %s, got %q (the API has no form-encoded option)", strings.Join(webhookContentTypes, ", "), ct)
+	}
+	return ct, nil
+}
 
-	customBody := map[string]string{
-		"category": category,
-		"title":    title,
-		"url":      url,
+// boolArg parses an optional "true"/"false" arg; a missing arg is false.
+func boolArg(args map[string]string, name string) (bool, error) {
+	v, ok := args[name]
+	if !ok || v == "" {
+		return false, nil
 	}
-	customBodyJSON, err := json.Marshal(customBody)
+	b, err := strconv.ParseBool(v)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling debug body: %w", err)
+		return false, fmt.Errorf("%s must be \"true\" or \"false\", got %q", name, v)
+	}
+	return b, nil
+}
+
+// relatedWorkAuthHeader returns the Authorization header add_release_related_work sends.
+func relatedWorkAuthHeader(webhookUser, webhookToken string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(webhookUser+":"+webhookToken))
+}
+
+// redactedHeaderValue is what the API returns for a secure header's value.
+const redactedHeaderValue = "***"
+
+// foreignWebhookAuth counts the add_release_related_work webhooks (at any
+// nesting depth) whose secure Authorization header differs from expected. The
+// parser doesn't carry headers into the model, so the next apply would replace
+// them with the provider's webhook credentials. Values the API redacted can't
+// be compared, so they're skipped.
+func foreignWebhookAuth(raws []json.RawMessage, expected string) int {
+	count := 0
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if m["type"] == "jira.issue.outgoing.webhook" {
+			value, _ := m["value"].(map[string]interface{})
+			url, _ := value["url"].(string)
+			headers, _ := value["headers"].([]interface{})
+			if relatedworkURLPattern.MatchString(url) {
+				for _, h := range headers {
+					header, _ := h.(map[string]interface{})
+					if header["name"] == "Authorization" && header["headerSecure"] == true &&
+						header["value"] != redactedHeaderValue && header["value"] != expected {
+						count++
+					}
+				}
+			}
+		}
+		if children, ok := m["children"].([]interface{}); ok {
+			for _, child := range children {
+				walk(child)
+			}
+		}
+	}
+
+	for _, raw := range raws {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			continue
+		}
+		walk(v)
 	}
+	return count
+}
+
+// buildDebugLogs returns 4 log actions that dump useful runtime info for add_release_related_work.
+func buildDebugLogs(args map[string]string, cloudID, prefix string) ([]json.RawMessage, error) {
+	webhookURL := fmt.Sprintf(
+		"https://api.atlassian.com/ex/jira/%s/rest/api/3/version/{{issue.%s.format(\"###\")}}/relatedwork",
+		cloudID, args["version_field"],
+	)
 
-	messages := []string{
-		debugLogPrefix + "webhook_url = " + webhookURL,
-		debugLogPrefix + "request_body = " + string(customBodyJSON),
-		debugLogPrefix + fmt.Sprintf("version_field_value = {{issue.%s}}", versionField),
-		debugLogPrefix + fmt.Sprintf("version_id = {{issue.%s.format(\"###\")}}", versionField),
+	messages, err := debugLogMessages(prefix, webhookURL, args)
+	if err != nil {
+		return nil, err
 	}
 
 	var logs []json.RawMessage
@@ -283,9 +643,119 @@ func buildDebugLogs(args map[string]string, cloudID string) ([]json.RawMessage,
 	return logs, nil
 }
 
+// debugLogMessages returns the exact debug log messages for an
+// add_release_related_work action with the given webhook URL and args.
+func debugLogMessages(prefix, webhookURL string, args map[string]string) ([]string, error) {
+	versionField := args["version_field"]
+	customBody := map[string]string{
+		"category": args["category"],
+		"title":    args["title"],
+		"url":      args["url"],
+	}
+	customBodyJSON, err := json.Marshal(customBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling debug body: %w", err)
+	}
+
+	return []string{
+		prefix + debugLogLabels[0] + webhookURL,
+		prefix + debugLogLabels[1] + string(customBodyJSON),
+		prefix + debugLogLabels[2] + fmt.Sprintf("{{issue.%s}}", versionField),
+		prefix + debugLogLabels[3] + fmt.Sprintf("{{issue.%s.format(\"###\")}}", versionField),
+	}, nil
+}
+
+// debugLogLabels start the messages debugLogMessages returns, after the prefix.
+var debugLogLabels = [debugLogCount]string{"webhook_url = ", "request_body = ", "version_field_value = ", "version_id = "}
+
+// strayDebugLogs counts the log actions, at any nesting depth, that look like
+// generated debug logs but aren't part of a run isDebugLogRun recognizes, e.g.
+// because they were reordered, edited or partly deleted in Jira. The parsers
+// keep them as plain log actions rather than guess which action they belong
+// to, so a config with debug = "true" shows a diff.
+func strayDebugLogs(raws []json.RawMessage, prefix string) int {
+	count := 0
+	for i := 0; i < len(raws); i++ {
+		if isDebugLogRun(raws, i, prefix) {
+			i += debugLogCount - 1
+			continue
+		}
+		var action struct {
+			Type     string            `json:"type"`
+			Value    json.RawMessage   `json:"value"`
+			Children []json.RawMessage `json:"children"`
+		}
+		if err := json.Unmarshal(raws[i], &action); err != nil {
+			continue
+		}
+		var msg string
+		if action.Type == "codebarrel.action.log" && json.Unmarshal(action.Value, &msg) == nil {
+			for _, label := range debugLogLabels {
+				if strings.HasPrefix(msg, prefix+label) {
+					count++
+					break
+				}
+			}
+		}
+		count += strayDebugLogs(action.Children, prefix)
+	}
+	return count
+}
+
+// isDebugLogRun reports whether raws[i:] starts with exactly the debug logs
+// buildDebugLogs generates for the add_release_related_work action that follows
+// them. Matching whole messages rather than the prefix alone means a user log
+// that merely starts with the prefix is never swallowed.
+func isDebugLogRun(raws []json.RawMessage, i int, prefix string) bool {
+	if i+debugLogCount >= len(raws) {
+		return false
+	}
+
+	webhook := raws[i+debugLogCount]
+	var envelope struct {
+		Type  string `json:"type"`
+		Value struct {
+			URL string `json:"url"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(webhook, &envelope); err != nil || envelope.Type != "jira.issue.outgoing.webhook" {
+		return false
+	}
+	args, err := parseAddReleaseRelatedWork(webhook)
+	if err != nil {
+		return false
+	}
+	want, err := debugLogMessages(prefix, envelope.Value.URL, args)
+	if err != nil {
+		return false
+	}
+
+	for j, msg := range want {
+		var logAction struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(raws[i+j], &logAction); err != nil {
+			return false
+		}
+		if logAction.Type != "codebarrel.action.log" || logAction.Value != msg {
+			return false
+		}
+	}
+	return true
+}
+
 // buildActionWithDebug builds one or more actions for the given type and args.
 // For add_release_related_work with debug="true", it prepends 4 debug log actions.
-func buildActionWithDebug(actionType string, args map[string]string, cloudID, webhookUser, webhookToken string) ([]json.RawMessage, error) {
+func buildActionWithDebug(actionType string, args map[string]string, cloudID, webhookUser, webhookToken, debugPrefix string) ([]json.RawMessage, error) {
+	def, ok := componentRegistry[actionType]
+	if !ok {
+		return nil, fmt.Errorf("unknown action type %q", actionType)
+	}
+	if err := validateArgs(actionType, def.args, args); err != nil {
+		return nil, err
+	}
+
 	if actionType == "add_release_related_work" && args["debug"] == "true" {
 		// Strip debug from args before building the real action.
 		buildArgs := make(map[string]string, len(args))
@@ -295,7 +765,7 @@ func buildActionWithDebug(actionType string, args map[string]string, cloudID, we
 			}
 		}
 
-		debugLogs, err := buildDebugLogs(buildArgs, cloudID)
+		debugLogs, err := buildDebugLogs(buildArgs, cloudID, debugPrefix)
 		if err != nil {
 			return nil, err
 		}
@@ -306,10 +776,6 @@ func buildActionWithDebug(actionType string, args map[string]string, cloudID, we
 		return append(debugLogs, webhookRaw), nil
 	}
 
-	def, ok := componentRegistry[actionType]
-	if !ok {
-		return nil, fmt.Errorf("unknown action type %q", actionType)
-	}
 	raw, err := def.build(args, cloudID, webhookUser, webhookToken)
 	if err != nil {
 		return nil, err
@@ -326,19 +792,138 @@ func parseLog(raw json.RawMessage) (map[string]string, error) {
 	if err := json.Unmarshal(raw, &action); err != nil {
 		return nil, fmt.Errorf("parsing log action: %w", err)
 	}
+	if m := logLevelPattern.FindStringSubmatch(action.Value); m != nil {
+		return map[string]string{
+			"level":   strings.ToLower(m[1]),
+			"message": strings.TrimPrefix(action.Value, m[0]),
+		}, nil
+	}
 	return map[string]string{"message": action.Value}, nil
 }
 
 func parseComment(raw json.RawMessage) (map[string]string, error) {
 	var action struct {
 		Value struct {
-			Comment string `json:"comment"`
+			Comment    json.RawMessage `json:"comment"`
+			Visibility *struct {
+				Type  string `json:"type"`
+				Value string `json:"value"`
+			} `json:"commentVisibility"`
 		} `json:"value"`
 	}
 	if err := json.Unmarshal(raw, &action); err != nil {
 		return nil, fmt.Errorf("parsing comment action: %w", err)
 	}
-	return map[string]string{"message": action.Value.Comment}, nil
+	var args map[string]string
+	// The comment is wiki markup as a string, or an ADF document object.
+	// Other shapes, e.g. from another schemaVersion, are read as raw.
+	var msg string
+	if len(action.Value.Comment) == 0 || json.Unmarshal(action.Value.Comment, &msg) == nil {
+		args = map[string]string{"message": msg}
+	} else {
+		doc, err := adfDocument(string(action.Value.Comment))
+		if err != nil {
+			return nil, fmt.Errorf("parsing comment action: %w", errUnsupportedShape)
+		}
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		args = map[string]string{"message": string(out), "body_format": "adf"}
+	}
+	if vis := action.Value.Visibility; vis != nil && (vis.Type != "" || vis.Value != "") {
+		args["visibility_type"] = vis.Type
+		args["visibility_value"] = vis.Value
+		if commentVisibilityError(args) != nil {
+			return nil, fmt.Errorf("parsing comment action: visibility %q: %w", vis.Type, errUnsupportedShape)
+		}
+	}
+	return args, nil
+}
+
+// adfDocument decodes an Atlassian Document Format body. Re-encoding the
+// result gives compact JSON with sorted keys, the same form jsonencode
+// produces, so ADF messages round-trip without a diff.
+func adfDocument(s string) (map[string]interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil || doc == nil {
+		return nil, fmt.Errorf("comment body_format = adf requires message to be a JSON object (an ADF document, e.g. built with jsonencode)")
+	}
+	if doc["type"] != "doc" {
+		return nil, fmt.Errorf("comment ADF message must have \"type\": \"doc\" at the top level, got %v", doc["type"])
+	}
+	return doc, nil
+}
+
+// parseAssignIssue reads back what buildAssignIssue writes. Other assign
+// types (automatic, copy from field, ...) are read as raw.
+func parseAssignIssue(raw json.RawMessage) (map[string]string, error) {
+	var action struct {
+		Value struct {
+			AssignType string   `json:"assignType"`
+			Assignee   *nameRef `json:"assignee"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return nil, fmt.Errorf("parsing assign_issue action: %w", err)
+	}
+	switch a := action.Value.Assignee; {
+	case action.Value.AssignType == "UNASSIGN":
+		return map[string]string{}, nil
+	case action.Value.AssignType == "SPECIFY_USER" && a != nil && (a.Type == "ID" || a.Type == "SMART") && a.Value != "":
+		return map[string]string{"assignee": a.Value}, nil
+	}
+	return nil, fmt.Errorf("parsing assign_issue action: assign type %q: %w", action.Value.AssignType, errUnsupportedShape)
+}
+
+func parseSetProperty(raw json.RawMessage) (map[string]string, error) {
+	var action struct {
+		Value struct {
+			EntityType string          `json:"entityType"`
+			Key        string          `json:"key"`
+			Value      json.RawMessage `json:"value"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return nil, fmt.Errorf("parsing set_property action: %w", err)
+	}
+	if action.Value.EntityType != "" && action.Value.EntityType != "ISSUE" {
+		return nil, fmt.Errorf("set_property only supports ISSUE entity properties, got %q; use components_json escape hatch", action.Value.EntityType)
+	}
+	args := map[string]string{"key": action.Value.Key}
+	// Strings (and a missing value) read back as value; any other JSON
+	// type as value_json, with numbers kept exactly.
+	var s string
+	if v := action.Value.Value; len(v) == 0 || string(v) == "null" {
+		args["value"] = ""
+	} else if err := json.Unmarshal(v, &s); err == nil {
+		args["value"] = s
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(v))
+		dec.UseNumber()
+		var decoded interface{}
+		if err := dec.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("parsing set_property value: %w", err)
+		}
+		norm, err := json.Marshal(decoded)
+		if err != nil {
+			return nil, fmt.Errorf("encoding set_property value: %w", err)
+		}
+		args["value_json"] = string(norm)
+	}
+	return args, nil
+}
+
+// parseRaw returns the component's JSON without the fields the API assigns,
+// the same normalization components_json gets.
+func parseRaw(raw json.RawMessage) (map[string]string, error) {
+	norm, err := normalizeRawJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing raw component: %w", err)
+	}
+	return map[string]string{"json": norm}, nil
 }
 
 // relatedworkURLPattern matches the webhook URL pattern for add_release_related_work.
@@ -349,8 +934,11 @@ var relatedworkURLPattern = regexp.MustCompile(
 func parseAddReleaseRelatedWork(raw json.RawMessage) (map[string]string, error) {
 	var action struct {
 		Value struct {
-			URL        string `json:"url"`
-			CustomBody string `json:"customBody"`
+			URL             string `json:"url"`
+			CustomBody      string `json:"customBody"`
+			ContentType     string `json:"contentType"`
+			ContinueOnError bool   `json:"continueOnErrorEnabled"`
+			ResponseEnabled bool   `json:"responseEnabled"`
 		} `json:"value"`
 	}
 	if err := json.Unmarshal(raw, &action); err != nil {
@@ -368,28 +956,32 @@ func parseAddReleaseRelatedWork(raw json.RawMessage) (map[string]string, error)
 		return nil, fmt.Errorf("parsing webhook custom body: %w", err)
 	}
 
-	return map[string]string{
+	args := map[string]string{
 		"version_field": versionField,
 		"category":      body["category"],
 		"title":         body["title"],
 		"url":           body["url"],
-	}, nil
+	}
+	// Defaults are left implicit so configs that omit these args round-trip;
+	// preserveDefaultArgs keeps the ones a config sets explicitly.
+	if action.Value.ContentType != "" && action.Value.ContentType != "custom" {
+		args["content_type"] = action.Value.ContentType
+	}
+	if action.Value.ContinueOnError {
+		args["continue_on_error"] = "true"
+	}
+	if action.Value.ResponseEnabled {
+		args["response_enabled"] = "true"
+	}
+	return args, nil
 }
 
 // --- Condition builder ---
 
-// BuildConditionJSON builds the 3-layer condition container JSON.
-func BuildConditionJSON(condArgs map[string]string, thenActions, elseActions []json.RawMessage) (json.RawMessage, error) {
-	first := condArgs["first"]
-	operator := condArgs["operator"]
-	second := condArgs["second"]
-	if first == "" || operator == "" {
-		return nil, fmt.Errorf("condition requires 'first' and 'operator' args")
-	}
-
-	// The comparator condition inside the IF block.
-	// The API expects uppercase operator values (EQUALS, NOT_EQUALS, etc.).
-	comparator := map[string]interface{}{
+// buildComparator builds a jira.comparator.condition component.
+// The API expects uppercase operator values (EQUALS, NOT_EQUALS, etc.).
+func buildComparator(first, operator, second string) map[string]interface{} {
+	return map[string]interface{}{
 		"children":      []interface{}{},
 		"component":     "CONDITION",
 		"conditions":    []interface{}{},
@@ -402,16 +994,235 @@ func BuildConditionJSON(condArgs map[string]string, thenActions, elseActions []j
 			"second":   second,
 		},
 	}
+}
 
-	// Convert thenActions from json.RawMessage to interface{} for nesting.
-	thenChildren := make([]interface{}, len(thenActions))
-	for i, raw := range thenActions {
-		var v interface{}
-		if err := json.Unmarshal(raw, &v); err != nil {
-			return nil, fmt.Errorf("parsing then action %d: %w", i, err)
-		}
-		thenChildren[i] = v
-	}
+// userConditionChecks are the jira.user.condition checks, lowercased as
+// user_condition accepts them.
+var userConditionChecks = []string{"user_is", "user_is_not", "in_group", "not_in_group"}
+
+// defaultUserField is the user a user_condition checks when `user` is unset:
+// the one who triggered the rule.
+const defaultUserField = "initiator"
+
+// buildUserCondition builds a jira.user.condition component with a single
+// check of the user in field against value. The API expects uppercase checks
+// (IN_GROUP, USER_IS, etc.).
+func buildUserCondition(check, value, field string) map[string]interface{} {
+	if field == "" {
+		field = defaultUserField
+	}
+	return map[string]interface{}{
+		"children":      []interface{}{},
+		"component":     "CONDITION",
+		"conditions":    []interface{}{},
+		"connectionId":  nil,
+		"schemaVersion": 6,
+		"type":          "jira.user.condition",
+		"value": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"field":    field,
+					"check":    strings.ToUpper(check),
+					"criteria": []interface{}{value},
+				},
+			},
+			"operator": "AND",
+		},
+	}
+}
+
+// buildJQLCondition builds a jira.jql.condition component.
+func buildJQLCondition(jql string) map[string]interface{} {
+	return map[string]interface{}{
+		"children":      []interface{}{},
+		"component":     "CONDITION",
+		"conditions":    []interface{}{},
+		"connectionId":  nil,
+		"schemaVersion": 1,
+		"type":          "jira.jql.condition",
+		"value": map[string]interface{}{
+			"jql": jql,
+		},
+	}
+}
+
+// whenCondition builds the condition a when map describes: a JQL condition
+// for {jql}, otherwise a comparator from first, operator and second.
+func whenCondition(whenArgs map[string]string) (map[string]interface{}, error) {
+	if jql, ok := whenArgs["jql"]; ok {
+		if len(whenArgs) > 1 || jql == "" {
+			return nil, fmt.Errorf("when with 'jql' takes a non-empty query and no other keys")
+		}
+		return buildJQLCondition(jql), nil
+	}
+	first := whenArgs["first"]
+	operator := whenArgs["operator"]
+	if first == "" || operator == "" {
+		return nil, fmt.Errorf("when requires 'first' and 'operator' keys, or 'jql'")
+	}
+	return buildComparator(first, operator, whenArgs["second"]), nil
+}
+
+// attachWhen adds the condition built from whenArgs to the action's own
+// conditions array, so the API only runs the action when it holds. With debug
+// logs the guarded action is the last one; the logs themselves run
+// unconditionally. Triggers have the same conditions array, so it also gates
+// a trigger.
+func attachWhen(raws []json.RawMessage, whenArgs map[string]string) ([]json.RawMessage, error) {
+	if len(whenArgs) == 0 || len(raws) == 0 {
+		return raws, nil
+	}
+	cond, err := whenCondition(whenArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	last := len(raws) - 1
+	var action map[string]interface{}
+	if err := json.Unmarshal(raws[last], &action); err != nil {
+		return nil, fmt.Errorf("parsing action for when: %w", err)
+	}
+	action["conditions"] = []interface{}{cond}
+	raw, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling action with when: %w", err)
+	}
+
+	out := append([]json.RawMessage{}, raws[:last]...)
+	return append(out, raw), nil
+}
+
+// parseWhen extracts an action- or trigger-level condition (set via `when`)
+// from the conditions array. Returns a null map when there is none.
+func parseWhen(raw json.RawMessage, reverse map[string]string) (types.Map, error) {
+	var action struct {
+		Conditions []struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"conditions"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return types.MapNull(types.StringType), fmt.Errorf("parsing action conditions: %w", err)
+	}
+	if len(action.Conditions) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+	if len(action.Conditions) > 1 {
+		return types.MapNull(types.StringType), fmt.Errorf("action has %d conditions; only a single when condition is supported, use components_json escape hatch", len(action.Conditions))
+	}
+	cond := action.Conditions[0]
+
+	var whenArgs map[string]string
+	switch cond.Type {
+	case "jira.comparator.condition":
+		var v struct {
+			First    string `json:"first"`
+			Operator string `json:"operator"`
+			Second   string `json:"second"`
+		}
+		if err := json.Unmarshal(cond.Value, &v); err != nil {
+			return types.MapNull(types.StringType), fmt.Errorf("parsing when comparator: %w", err)
+		}
+		whenArgs = map[string]string{
+			"first":    v.First,
+			"operator": strings.ToLower(v.Operator),
+			"second":   v.Second,
+		}
+	case "jira.jql.condition":
+		var v struct {
+			JQL string `json:"jql"`
+		}
+		if err := json.Unmarshal(cond.Value, &v); err != nil {
+			return types.MapNull(types.StringType), fmt.Errorf("parsing when JQL condition: %w", err)
+		}
+		whenArgs = map[string]string{"jql": v.JQL}
+	default:
+		return types.MapNull(types.StringType), fmt.Errorf("action condition type %q is not supported by when; use components_json escape hatch", cond.Type)
+	}
+	return stringMapToTypesMapInner(unresolveAliases(whenArgs, reverse))
+}
+
+// BuildConditionJSON builds the 3-layer condition container JSON, with a
+// comparator from first, operator and second, or condition_json verbatim.
+func BuildConditionJSON(condArgs map[string]string, thenActions, elseActions []json.RawMessage) (json.RawMessage, error) {
+	matchType, err := conditionMatchType(condArgs)
+	if err != nil {
+		return nil, err
+	}
+	if condJSON, ok := condArgs["condition_json"]; ok {
+		for name := range condArgs {
+			if name != "condition_json" && name != "match_type" {
+				return nil, fmt.Errorf("condition with 'condition_json' takes no other args except 'match_type'")
+			}
+		}
+		var cond map[string]interface{}
+		if err := json.Unmarshal([]byte(condJSON), &cond); err != nil || cond == nil {
+			return nil, fmt.Errorf("condition condition_json must be a JSON object")
+		}
+		if cond["component"] != "CONDITION" {
+			return nil, fmt.Errorf("condition condition_json must have \"component\": \"CONDITION\"")
+		}
+		if s, _ := cond["type"].(string); s == "" {
+			return nil, fmt.Errorf("condition condition_json must have a non-empty \"type\" key")
+		}
+		return buildConditionContainer(cond, matchType, thenActions, elseActions)
+	}
+	first := condArgs["first"]
+	operator := condArgs["operator"]
+	second := condArgs["second"]
+	if first == "" || operator == "" {
+		return nil, fmt.Errorf("condition requires 'first' and 'operator' args, or 'condition_json'")
+	}
+	return buildConditionContainer(buildComparator(first, operator, second), matchType, thenActions, elseActions)
+}
+
+// BuildUserConditionJSON builds the condition container JSON for a
+// user_condition, with a jira.user.condition in place of the comparator.
+func BuildUserConditionJSON(condArgs map[string]string, thenActions, elseActions []json.RawMessage) (json.RawMessage, error) {
+	check := condArgs["check"]
+	value := condArgs["value"]
+	if check == "" || value == "" {
+		return nil, fmt.Errorf("user_condition requires 'check' and 'value' args")
+	}
+	if !slices.Contains(userConditionChecks, strings.ToLower(check)) {
+		return nil, fmt.Errorf("user_condition check must be one of %s, got %q", strings.Join(userConditionChecks, ", "), check)
+	}
+	matchType, err := conditionMatchType(condArgs)
+	if err != nil {
+		return nil, err
+	}
+	return buildConditionContainer(buildUserCondition(check, value, condArgs["user"]), matchType, thenActions, elseActions)
+}
+
+// conditionMatchTypes are the accepted values of the match_type condition arg.
+var conditionMatchTypes = []string{"all", "any"}
+
+// conditionMatchType returns the IF block's conditionMatchType for the
+// match_type arg, ALL when it's unset.
+func conditionMatchType(condArgs map[string]string) (string, error) {
+	m, ok := condArgs["match_type"]
+	if !ok {
+		return "ALL", nil
+	}
+	if !slices.Contains(conditionMatchTypes, strings.ToLower(m)) {
+		return "", fmt.Errorf("condition match_type must be one of %s, got %q", strings.Join(conditionMatchTypes, ", "), m)
+	}
+	return strings.ToUpper(m), nil
+}
+
+// buildConditionContainer wraps condition and the then/else actions in the
+// container → IF/ELSE block layers the API expects. matchType is the IF
+// block's conditionMatchType.
+func buildConditionContainer(condition map[string]interface{}, matchType string, thenActions, elseActions []json.RawMessage) (json.RawMessage, error) {
+	// Convert thenActions from json.RawMessage to interface{} for nesting.
+	thenChildren := make([]interface{}, len(thenActions))
+	for i, raw := range thenActions {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("parsing then action %d: %w", i, err)
+		}
+		thenChildren[i] = v
+	}
 
 	elseChildren := make([]interface{}, len(elseActions))
 	for i, raw := range elseActions {
@@ -422,18 +1233,18 @@ func BuildConditionJSON(condArgs map[string]string, thenActions, elseActions []j
 		elseChildren[i] = v
 	}
 
-	// IF block (first CONDITION_BLOCK): has the comparator condition + then children.
+	// IF block (first CONDITION_BLOCK): has the condition + then children.
 	ifBlock := map[string]interface{}{
 		"children":  thenChildren,
 		"component": "CONDITION_BLOCK",
 		"conditions": []interface{}{
-			comparator,
+			condition,
 		},
 		"connectionId":  nil,
 		"schemaVersion": 1,
 		"type":          "jira.condition.if.block",
 		"value": map[string]interface{}{
-			"conditionMatchType": "ALL",
+			"conditionMatchType": matchType,
 		},
 	}
 
@@ -467,9 +1278,88 @@ func BuildConditionJSON(condArgs map[string]string, thenActions, elseActions []j
 	return json.Marshal(container)
 }
 
+// BuildBranchJSON builds a BRANCH component from its configuration JSON, with
+// thenActions as its children.
+func BuildBranchJSON(branchArgs map[string]string, thenActions []json.RawMessage) (json.RawMessage, error) {
+	var branch map[string]interface{}
+	if err := json.Unmarshal([]byte(branchArgs["json"]), &branch); err != nil {
+		return nil, fmt.Errorf("branch json must be a JSON object: %w", err)
+	}
+	if branch["component"] != "BRANCH" {
+		return nil, fmt.Errorf("branch json must have \"component\": \"BRANCH\"")
+	}
+	if s, _ := branch["type"].(string); s == "" {
+		return nil, fmt.Errorf("branch json must have a non-empty \"type\" key")
+	}
+	if children, _ := branch["children"].([]interface{}); len(children) > 0 {
+		return nil, fmt.Errorf("branch json must not contain children; put the branch's actions in then")
+	}
+
+	children := make([]interface{}, len(thenActions))
+	for i, raw := range thenActions {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("parsing then action %d: %w", i, err)
+		}
+		children[i] = v
+	}
+	branch["children"] = children
+
+	return json.Marshal(branch)
+}
+
+// --- Branch parser ---
+
+// parseBranch splits a BRANCH component into its configuration, kept as the
+// json arg, and its children, parsed like a condition's then actions.
+func parseBranch(raw json.RawMessage, ctx context.Context, reverse map[string]string, debugPrefix string) (*componentModel, error) {
+	var branch map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &branch); err != nil {
+		return nil, fmt.Errorf("parsing branch: %w", err)
+	}
+	var children []json.RawMessage
+	if c, ok := branch["children"]; ok {
+		if err := json.Unmarshal(c, &children); err != nil {
+			return nil, fmt.Errorf("parsing branch children: %w", err)
+		}
+	}
+	delete(branch, "children")
+
+	config, err := json.Marshal(branch)
+	if err != nil {
+		return nil, fmt.Errorf("parsing branch: %w", err)
+	}
+	norm, err := normalizeRawJSON(config)
+	if err != nil {
+		return nil, fmt.Errorf("parsing branch: %w", err)
+	}
+
+	thenActions, err := parseInnerActions(children, reverse, debugPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("parsing branch actions: %w", err)
+	}
+
+	argsMap, err := stringMapToTypesMap(ctx, unresolveAliases(map[string]string{"json": norm}, reverse))
+	if err != nil {
+		return nil, err
+	}
+
+	// Then is nil when empty, restored from config by preserveEmptyBranches.
+	model := &componentModel{
+		Key:  types.StringNull(),
+		Type: types.StringValue(branchComponentType),
+		Args: argsMap,
+		When: types.MapNull(types.StringType),
+	}
+	if len(thenActions) > 0 {
+		model.Then = thenActions
+	}
+	return model, nil
+}
+
 // --- Condition parser ---
 
-func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse map[string]string) (*componentModel, error) {
+func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse map[string]string, debugPrefix string) (*componentModel, error) {
 	var container struct {
 		Children []json.RawMessage `json:"children"`
 	}
@@ -479,40 +1369,50 @@ func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse m
 	if len(container.Children) < 1 {
 		return nil, fmt.Errorf("condition container has no children")
 	}
+	// Each "else if" adds another CONDITION_BLOCK; the structured model only
+	// has then/else, so refuse rather than drop branches.
+	if len(container.Children) > 2 {
+		return nil, fmt.Errorf("condition has %d blocks (else-if branches); only if/else is supported, use components_json escape hatch", len(container.Children))
+	}
 
 	// Parse IF block (first child).
 	var ifBlock struct {
 		Conditions []json.RawMessage `json:"conditions"`
 		Children   []json.RawMessage `json:"children"`
+		Value      struct {
+			ConditionMatchType string `json:"conditionMatchType"`
+		} `json:"value"`
 	}
 	if err := json.Unmarshal(container.Children[0], &ifBlock); err != nil {
 		return nil, fmt.Errorf("parsing IF block: %w", err)
 	}
 
-	// Extract condition args from the comparator.
+	// Extract condition args; the condition's type decides the component type.
 	if len(ifBlock.Conditions) < 1 {
 		return nil, fmt.Errorf("IF block has no conditions")
 	}
-	var comparator struct {
-		Value struct {
-			First    string `json:"first"`
-			Operator string `json:"operator"`
-			Second   string `json:"second"`
-		} `json:"value"`
-	}
-	if err := json.Unmarshal(ifBlock.Conditions[0], &comparator); err != nil {
-		return nil, fmt.Errorf("parsing comparator condition: %w", err)
+	// The structured model has a single condition; refuse rather than drop
+	// the others, whose ALL/ANY combination would change meaning.
+	if len(ifBlock.Conditions) > 1 {
+		return nil, fmt.Errorf("IF block has %d conditions; only a single condition is supported, use components_json escape hatch", len(ifBlock.Conditions))
 	}
-
-	condArgs := map[string]string{
-		"first":    comparator.Value.First,
-		"operator": strings.ToLower(comparator.Value.Operator),
-		"second":   comparator.Value.Second,
+	// Conditions without a structured form read back as condition_json.
+	compType, condArgs, err := parseIfCondition(ifBlock.Conditions[0])
+	if err != nil {
+		norm, normErr := normalizeRawJSON(ifBlock.Conditions[0])
+		if normErr != nil {
+			return nil, err
+		}
+		compType, condArgs = "condition", map[string]string{"condition_json": norm}
 	}
 	condArgs = unresolveAliases(condArgs, reverse)
+	// ALL is the default, so only a different match type becomes an arg.
+	if m := ifBlock.Value.ConditionMatchType; m != "" && !strings.EqualFold(m, "ALL") {
+		condArgs["match_type"] = strings.ToLower(m)
+	}
 
 	// Parse THEN actions from IF block children.
-	thenActions, err := parseInnerActions(ifBlock.Children, reverse)
+	thenActions, err := parseInnerActions(ifBlock.Children, reverse, debugPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("parsing then actions: %w", err)
 	}
@@ -521,13 +1421,18 @@ func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse m
 	var elseActions []innerActionModel
 	if len(container.Children) >= 2 {
 		var elseBlock struct {
-			Children []json.RawMessage `json:"children"`
+			Conditions []json.RawMessage `json:"conditions"`
+			Children   []json.RawMessage `json:"children"`
 		}
 		if err := json.Unmarshal(container.Children[1], &elseBlock); err != nil {
 			return nil, fmt.Errorf("parsing ELSE block: %w", err)
 		}
+		// A second block with its own conditions is an else-if, not an else.
+		if len(elseBlock.Conditions) > 0 {
+			return nil, fmt.Errorf("condition has an else-if branch; only if/else is supported, use components_json escape hatch")
+		}
 		if len(elseBlock.Children) > 0 {
-			elseActions, err = parseInnerActions(elseBlock.Children, reverse)
+			elseActions, err = parseInnerActions(elseBlock.Children, reverse, debugPrefix)
 			if err != nil {
 				return nil, fmt.Errorf("parsing else actions: %w", err)
 			}
@@ -539,12 +1444,17 @@ func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse m
 		return nil, err
 	}
 
+	// Then/Else are nil when empty; readIntoModel restores an explicit empty
+	// list from config via preserveEmptyBranches (avoids null vs empty plan diff).
 	model := &componentModel{
-		Type: types.StringValue("condition"),
+		Key:  types.StringNull(),
+		Type: types.StringValue(compType),
 		Args: argsMap,
-		Then: thenActions,
+		When: types.MapNull(types.StringType),
+	}
+	if len(thenActions) > 0 {
+		model.Then = thenActions
 	}
-	// Only set Else if there are actual actions (avoids null vs empty plan diff).
 	if len(elseActions) > 0 {
 		model.Else = elseActions
 	}
@@ -552,14 +1462,378 @@ func parseConditionContainer(raw json.RawMessage, ctx context.Context, reverse m
 	return model, nil
 }
 
+// parseIfCondition returns the component type and args for the condition in
+// a container's IF block: a comparator (condition) or a user check
+// (user_condition). Other condition types have no structured form.
+func parseIfCondition(raw json.RawMessage) (string, map[string]string, error) {
+	var cond struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &cond); err != nil {
+		return "", nil, fmt.Errorf("parsing IF block condition: %w", err)
+	}
+
+	switch cond.Type {
+	case "jira.comparator.condition":
+		var v struct {
+			First    string `json:"first"`
+			Operator string `json:"operator"`
+			Second   string `json:"second"`
+		}
+		if err := json.Unmarshal(cond.Value, &v); err != nil {
+			return "", nil, fmt.Errorf("parsing comparator condition: %w", err)
+		}
+		return "condition", map[string]string{
+			"first":    v.First,
+			"operator": strings.ToLower(v.Operator),
+			"second":   v.Second,
+		}, nil
+	case "jira.user.condition":
+		var v struct {
+			Conditions []struct {
+				Field    string        `json:"field"`
+				Check    string        `json:"check"`
+				Criteria []interface{} `json:"criteria"`
+			} `json:"conditions"`
+		}
+		if err := json.Unmarshal(cond.Value, &v); err != nil {
+			return "", nil, fmt.Errorf("parsing user condition: %w", err)
+		}
+		// user_condition models one check against one value; anything
+		// richer (several checks, several users) stays in raw JSON.
+		if len(v.Conditions) != 1 || len(v.Conditions[0].Criteria) != 1 {
+			return "", nil, fmt.Errorf("user condition with %d checks is not supported; only a single check against a single value is, use components_json escape hatch", len(v.Conditions))
+		}
+		c := v.Conditions[0]
+		value, ok := c.Criteria[0].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("user condition criteria %v is not supported; use components_json escape hatch", c.Criteria[0])
+		}
+		args := map[string]string{"check": strings.ToLower(c.Check), "value": value}
+		if c.Field != defaultUserField {
+			args["user"] = c.Field
+		}
+		return "user_condition", args, nil
+	default:
+		return "", nil, fmt.Errorf("condition type %q is not supported; use components_json escape hatch", cond.Type)
+	}
+}
+
+// preserveEmptyBranches copies the empty-vs-null shape of then/else from the
+// prior model onto freshly parsed components. The API can't distinguish
+// `else = []` from an omitted else, so parsing always yields nil; if the
+// config used an explicit empty list, keep it so state matches config.
+func preserveEmptyBranches(prior, parsed []componentModel) {
+	for i := range parsed {
+		if i >= len(prior) {
+			return
+		}
+		if parsed[i].Then == nil && prior[i].Then != nil && len(prior[i].Then) == 0 {
+			parsed[i].Then = []innerActionModel{}
+		}
+		if parsed[i].Else == nil && prior[i].Else != nil && len(prior[i].Else) == 0 {
+			parsed[i].Else = []innerActionModel{}
+		}
+	}
+}
+
+// preserveDefaultArgs copies args the prior config set to their default value,
+// such as continue_on_error = "false", onto freshly parsed components and
+// their then/else actions. Parsers leave defaults out so configs that omit
+// them round-trip; an arg is kept only when the component builds the same
+// with it as without.
+func preserveDefaultArgs(ctx context.Context, prior, parsed []componentModel) {
+	for i := range parsed {
+		if i >= len(prior) {
+			return
+		}
+		parsed[i].Args = withDefaultArgs(ctx, parsed[i].Type, prior[i].Type, prior[i].Args, parsed[i].Args)
+		preserveInnerDefaultArgs(ctx, prior[i].Then, parsed[i].Then)
+		preserveInnerDefaultArgs(ctx, prior[i].Else, parsed[i].Else)
+	}
+}
+
+func preserveInnerDefaultArgs(ctx context.Context, prior, parsed []innerActionModel) {
+	for i := range parsed {
+		if i >= len(prior) {
+			return
+		}
+		parsed[i].Args = withDefaultArgs(ctx, parsed[i].Type, prior[i].Type, prior[i].Args, parsed[i].Args)
+	}
+}
+
+// withDefaultArgs returns parsed plus the args in prior it lacks that don't
+// change what the component builds. It returns parsed unchanged when the
+// types differ or either side can't be built.
+func withDefaultArgs(ctx context.Context, parsedType, priorType types.String, prior, parsed types.Map) types.Map {
+	if !parsedType.Equal(priorType) || prior.IsNull() || prior.IsUnknown() {
+		return parsed
+	}
+	priorArgs, err := typesMapToStringMap(ctx, prior)
+	if err != nil {
+		return parsed
+	}
+	args, err := typesMapToStringMap(ctx, parsed)
+	if err != nil {
+		return parsed
+	}
+	compType := parsedType.ValueString()
+	want, err := buildForComparison(compType, args)
+	if err != nil {
+		return parsed
+	}
+	added := false
+	for name, v := range priorArgs {
+		if _, ok := args[name]; ok {
+			continue
+		}
+		args[name] = v
+		if got, err := buildForComparison(compType, args); err != nil || !bytes.Equal(got, want) {
+			delete(args, name)
+			continue
+		}
+		added = true
+	}
+	if !added {
+		return parsed
+	}
+	m, err := stringMapToTypesMap(ctx, args)
+	if err != nil {
+		return parsed
+	}
+	return m
+}
+
+// buildForComparison builds a component's API JSON from its args alone, with
+// placeholder credentials, so two arg sets can be compared.
+func buildForComparison(compType string, args map[string]string) ([]byte, error) {
+	var raws []json.RawMessage
+	var err error
+	switch compType {
+	case "condition":
+		var raw json.RawMessage
+		raw, err = BuildConditionJSON(args, nil, nil)
+		raws = []json.RawMessage{raw}
+	case "user_condition":
+		var raw json.RawMessage
+		raw, err = BuildUserConditionJSON(args, nil, nil)
+		raws = []json.RawMessage{raw}
+	default:
+		raws, err = buildActionWithDebug(compType, args, "cloud", "user", "token", client.DefaultDebugLogPrefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(raws)
+}
+
+// preserveComponentKeys copies user keys from the prior model onto freshly
+// parsed components. The API has no field for them, so a key follows the prior
+// component with the same content (surviving reorders made in the Jira UI) and
+// falls back to the prior component at the same position.
+func preserveComponentKeys(prior, parsed []componentModel) {
+	used := make([]bool, len(prior))
+	matched := make([]bool, len(parsed))
+
+	for i := range parsed {
+		for j := range prior {
+			if !used[j] && !prior[j].Key.IsNull() && sameComponentContent(prior[j], parsed[i]) {
+				parsed[i].Key = prior[j].Key
+				used[j], matched[i] = true, true
+				break
+			}
+		}
+	}
+	for i := range parsed {
+		if !matched[i] && i < len(prior) && !used[i] {
+			parsed[i].Key = prior[i].Key
+			used[i] = true
+		}
+	}
+}
+
+// componentIDSources pairs planned components with the prior ones, in the
+// rule's current order, whose API IDs they keep on update: a keyed component
+// with the prior component of the same key, wherever it moved. Other
+// components pair with an unkeyed prior component at their position, as in
+// preserveComponentKeys. It returns nil, for pairing by position, unless both
+// sides have keys.
+func componentIDSources(prior, planned []componentModel) []int {
+	hasKey := func(c componentModel) bool { return !c.Key.IsNull() }
+	if !slices.ContainsFunc(prior, hasKey) || !slices.ContainsFunc(planned, hasKey) {
+		return nil
+	}
+	sources := make([]int, len(planned))
+	for i, c := range planned {
+		sources[i] = -1
+		if hasKey(c) {
+			if j := slices.IndexFunc(prior, func(p componentModel) bool { return p.Key.Equal(c.Key) }); j >= 0 {
+				sources[i] = j
+				continue
+			}
+		}
+		if i < len(prior) && !hasKey(prior[i]) {
+			sources[i] = i
+		}
+	}
+	return sources
+}
+
+// componentSpans returns how many API components each of components builds
+// to: one, except for top-level actions that expand, such as
+// add_release_related_work with debug = "true".
+func componentSpans(components []componentModel, cloudID, webhookUser, webhookToken, debugPrefix string, ctx context.Context, aliases map[string]string) ([]int, error) {
+	spans := make([]int, len(components))
+	for i, comp := range components {
+		raws, err := BuildComponentsJSON([]componentModel{comp}, cloudID, webhookUser, webhookToken, debugPrefix, ctx, aliases)
+		if err != nil {
+			return nil, fmt.Errorf("component %d: %w", i, err)
+		}
+		spans[i] = len(raws)
+	}
+	return spans, nil
+}
+
+// rawIDSources turns componentIDSources' pairing of structured components
+// into one over the API components they build to, given the spans of each
+// side from componentSpans. The API components of a paired structured
+// component pair in order; those without a counterpart, and those of an
+// unpaired component, get -1.
+func rawIDSources(sources, priorSpans, plannedSpans []int) []int {
+	if sources == nil {
+		return nil
+	}
+	priorStart := make([]int, len(priorSpans))
+	for j := 1; j < len(priorSpans); j++ {
+		priorStart[j] = priorStart[j-1] + priorSpans[j-1]
+	}
+	var raw []int
+	for i, src := range sources {
+		for k := 0; k < plannedSpans[i]; k++ {
+			if src >= 0 && k < priorSpans[src] {
+				raw = append(raw, priorStart[src]+k)
+			} else {
+				raw = append(raw, -1)
+			}
+		}
+	}
+	return raw
+}
+
+// preserveRawJSON keeps the prior json arg of raw and branch components, the
+// condition_json arg of conditions, and the json arg of raw then/else actions,
+// at the same position when it normalizes to what
+// the API returned. Formatting, API-assigned ids and schemaVersion upgrades then don't
+// show as drift, as with components_json.
+func preserveRawJSON(prior, parsed []componentModel) {
+	for i := 0; i < len(prior) && i < len(parsed); i++ {
+		parsed[i].Args = equivalentRawArgs(prior[i].Type, prior[i].Args, parsed[i].Type, parsed[i].Args)
+		for j := 0; j < len(prior[i].Then) && j < len(parsed[i].Then); j++ {
+			p, a := prior[i].Then[j], &parsed[i].Then[j]
+			a.Args = equivalentRawArgs(p.Type, p.Args, a.Type, a.Args)
+		}
+		for j := 0; j < len(prior[i].Else) && j < len(parsed[i].Else); j++ {
+			p, a := prior[i].Else[j], &parsed[i].Else[j]
+			a.Args = equivalentRawArgs(p.Type, p.Args, a.Type, a.Args)
+		}
+	}
+}
+
+// equivalentRawArgs returns priorArgs when both sides are raw (or branch, or
+// a condition with condition_json) and their JSON args are equivalent, and
+// parsedArgs otherwise.
+func equivalentRawArgs(priorType types.String, priorArgs types.Map, parsedType types.String, parsedArgs types.Map) types.Map {
+	key := "json"
+	switch t := parsedType.ValueString(); t {
+	case rawComponentType, branchComponentType:
+	case "condition":
+		key = "condition_json"
+	default:
+		return parsedArgs
+	}
+	if !priorType.Equal(parsedType) {
+		return parsedArgs
+	}
+	p, ok := priorArgs.Elements()[key].(types.String)
+	if !ok || p.IsNull() || p.IsUnknown() {
+		return parsedArgs
+	}
+	a, ok := parsedArgs.Elements()[key].(types.String)
+	if !ok {
+		return parsedArgs
+	}
+	norm, err := normalizeRawJSON(json.RawMessage(p.ValueString()))
+	if err != nil || withoutSchemaVersions(norm) != withoutSchemaVersions(a.ValueString()) {
+		return parsedArgs
+	}
+	return priorArgs
+}
+
+// preserveAliasForms keeps the prior args and when of components and
+// then/else actions at the same position when they differ from the parsed
+// ones only in alias form. Reading replaces every field ID that has an alias
+// with the alias, so a config that writes the field ID itself would otherwise
+// show a diff on every plan.
+func preserveAliasForms(prior, parsed []componentModel, aliases map[string]string) {
+	for i := 0; i < len(prior) && i < len(parsed); i++ {
+		p, a := prior[i], &parsed[i]
+		if !p.Type.Equal(a.Type) {
+			continue
+		}
+		a.Args = sameAliasForm(p.Args, a.Args, aliases)
+		a.When = sameAliasForm(p.When, a.When, aliases)
+		for j := 0; j < len(p.Then) && j < len(a.Then); j++ {
+			if p.Then[j].Type.Equal(a.Then[j].Type) {
+				a.Then[j].Args = sameAliasForm(p.Then[j].Args, a.Then[j].Args, aliases)
+				a.Then[j].When = sameAliasForm(p.Then[j].When, a.Then[j].When, aliases)
+			}
+		}
+		for j := 0; j < len(p.Else) && j < len(a.Else); j++ {
+			if p.Else[j].Type.Equal(a.Else[j].Type) {
+				a.Else[j].Args = sameAliasForm(p.Else[j].Args, a.Else[j].Args, aliases)
+				a.Else[j].When = sameAliasForm(p.Else[j].When, a.Else[j].When, aliases)
+			}
+		}
+	}
+}
+
+// sameAliasForm returns prior when it resolves to the same field IDs as
+// parsed, and parsed otherwise.
+func sameAliasForm(prior, parsed types.Map, aliases map[string]string) types.Map {
+	if len(aliases) == 0 || prior.IsNull() || prior.IsUnknown() || parsed.IsNull() || prior.Equal(parsed) {
+		return parsed
+	}
+	ctx := context.Background()
+	p, err := typesMapToStringMap(ctx, prior)
+	if err != nil {
+		return parsed
+	}
+	a, err := typesMapToStringMap(ctx, parsed)
+	if err != nil || !maps.Equal(resolveAliases(p, aliases), resolveAliases(a, aliases)) {
+		return parsed
+	}
+	return prior
+}
+
+func sameComponentContent(a, b componentModel) bool {
+	return a.Type.Equal(b.Type) && a.Args.Equal(b.Args) && a.When.Equal(b.When)
+}
+
 // parseInnerActions parses a list of action JSON blobs into innerActionModels.
-// It detects debug log actions (prefixed with debugLogPrefix), skips them, and
-// sets debug="true" on the following add_release_related_work action.
-func parseInnerActions(raws []json.RawMessage, reverse map[string]string) ([]innerActionModel, error) {
+// It detects the debug log actions generated for add_release_related_work
+// (see isDebugLogRun), skips them, and sets debug="true" on that action.
+func parseInnerActions(raws []json.RawMessage, reverse map[string]string, debugPrefix string) ([]innerActionModel, error) {
 	var actions []innerActionModel
-	sawDebugLog := false
 
-	for _, raw := range raws {
+	for i := 0; i < len(raws); i++ {
+		// Detect and skip debug log actions.
+		sawDebugLog := isDebugLogRun(raws, i, debugPrefix)
+		if sawDebugLog {
+			i += debugLogCount
+		}
+		raw := raws[i]
+
 		var envelope struct {
 			Type string `json:"type"`
 		}
@@ -567,27 +1841,12 @@ func parseInnerActions(raws []json.RawMessage, reverse map[string]string) ([]inn
 			return nil, fmt.Errorf("parsing action type: %w", err)
 		}
 
-		// Detect and skip debug log actions.
-		if envelope.Type == "codebarrel.action.log" {
-			var logAction struct {
-				Value string `json:"value"`
-			}
-			if err := json.Unmarshal(raw, &logAction); err != nil {
-				return nil, fmt.Errorf("parsing log value: %w", err)
-			}
-			if strings.HasPrefix(logAction.Value, debugLogPrefix) {
-				sawDebugLog = true
-				continue
-			}
-		}
-
-		// For outgoing webhooks, check if it matches our known pattern.
+		// Unknown API types, and outgoing webhooks other than the related
+		// work one, are kept verbatim as raw actions.
 		userType, ok := apiTypeToComponentUserType[envelope.Type]
 		if !ok {
-			return nil, fmt.Errorf("unrecognized component API type: %q", envelope.Type)
+			userType = rawComponentType
 		}
-
-		// For webhooks, we need to disambiguate.
 		if envelope.Type == "jira.issue.outgoing.webhook" {
 			var webhook struct {
 				Value struct {
@@ -598,31 +1857,34 @@ func parseInnerActions(raws []json.RawMessage, reverse map[string]string) ([]inn
 				return nil, fmt.Errorf("parsing webhook URL: %w", err)
 			}
 			if !strings.HasSuffix(webhook.Value.URL, "/relatedwork") {
-				return nil, fmt.Errorf("outgoing webhook URL %q does not match any known component type; use components_json escape hatch", webhook.Value.URL)
+				userType = rawComponentType
 			}
 		}
 
-		def := componentRegistry[userType]
-		args, err := def.parse(raw)
+		userType, args, err := parseAs(userType, raw)
 		if err != nil {
 			return nil, fmt.Errorf("parsing %s action: %w", userType, err)
 		}
 
 		// If we saw debug logs before this action, mark it with debug="true".
-		if sawDebugLog && userType == "add_release_related_work" {
+		if sawDebugLog {
 			args["debug"] = "true"
 		}
-		sawDebugLog = false
 
 		args = unresolveAliases(args, reverse)
 		argsMap, err := stringMapToTypesMapInner(args)
 		if err != nil {
 			return nil, err
 		}
+		when, err := parseWhen(raw, reverse)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s action: %w", userType, err)
+		}
 
 		actions = append(actions, innerActionModel{
 			Type: types.StringValue(userType),
 			Args: argsMap,
+			When: when,
 		})
 	}
 	return actions, nil
@@ -632,23 +1894,33 @@ func parseInnerActions(raws []json.RawMessage, reverse map[string]string) ([]inn
 
 // BuildComponentsJSON builds the full API components JSON from the structured components.
 // aliases maps friendly names → field IDs; pass nil for no alias resolution.
-func BuildComponentsJSON(components []componentModel, cloudID, webhookUser, webhookToken string, ctx context.Context, aliases map[string]string) ([]json.RawMessage, error) {
+func BuildComponentsJSON(components []componentModel, cloudID, webhookUser, webhookToken, debugPrefix string, ctx context.Context, aliases map[string]string) ([]json.RawMessage, error) {
 	var result []json.RawMessage
 
 	for i, comp := range components {
 		compType := comp.Type.ValueString()
 
-		if compType == "condition" {
+		if compType == "condition" || compType == "user_condition" {
+			if !comp.When.IsNull() && !comp.When.IsUnknown() {
+				return nil, fmt.Errorf("component %d: when is not supported on %s components; put the check in args", i, compType)
+			}
+			specs, build := conditionArgs, BuildConditionJSON
+			if compType == "user_condition" {
+				specs, build = userConditionArgs, BuildUserConditionJSON
+			}
 			// Build condition with then/else children.
 			condArgs, err := typesMapToStringMap(ctx, comp.Args)
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
 			condArgs = resolveAliases(condArgs, aliases)
+			if err := validateArgs(compType, specs, condArgs); err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
 
 			var thenActions []json.RawMessage
 			for j, action := range comp.Then {
-				raws, err := buildInnerAction(action, cloudID, webhookUser, webhookToken, ctx, aliases)
+				raws, err := buildInnerAction(action, cloudID, webhookUser, webhookToken, debugPrefix, ctx, aliases)
 				if err != nil {
 					return nil, fmt.Errorf("component %d then[%d]: %w", i, j, err)
 				}
@@ -657,14 +1929,44 @@ func BuildComponentsJSON(components []componentModel, cloudID, webhookUser, webh
 
 			var elseActions []json.RawMessage
 			for j, action := range comp.Else {
-				raws, err := buildInnerAction(action, cloudID, webhookUser, webhookToken, ctx, aliases)
+				raws, err := buildInnerAction(action, cloudID, webhookUser, webhookToken, debugPrefix, ctx, aliases)
 				if err != nil {
 					return nil, fmt.Errorf("component %d else[%d]: %w", i, j, err)
 				}
 				elseActions = append(elseActions, raws...)
 			}
 
-			raw, err := BuildConditionJSON(condArgs, thenActions, elseActions)
+			raw, err := build(condArgs, thenActions, elseActions)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			result = append(result, raw)
+		} else if compType == branchComponentType {
+			if !comp.When.IsNull() && !comp.When.IsUnknown() {
+				return nil, fmt.Errorf("component %d: when is not supported on branch components; put a condition in then", i)
+			}
+			if len(comp.Else) > 0 {
+				return nil, fmt.Errorf("component %d: else is not supported on branch components", i)
+			}
+			branchArgsMap, err := typesMapToStringMap(ctx, comp.Args)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			branchArgsMap = resolveAliases(branchArgsMap, aliases)
+			if err := validateArgs(compType, branchArgs, branchArgsMap); err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+
+			var thenActions []json.RawMessage
+			for j, action := range comp.Then {
+				raws, err := buildInnerAction(action, cloudID, webhookUser, webhookToken, debugPrefix, ctx, aliases)
+				if err != nil {
+					return nil, fmt.Errorf("component %d then[%d]: %w", i, j, err)
+				}
+				thenActions = append(thenActions, raws...)
+			}
+
+			raw, err := BuildBranchJSON(branchArgsMap, thenActions)
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
@@ -676,7 +1978,15 @@ func BuildComponentsJSON(components []componentModel, cloudID, webhookUser, webh
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
 			args = resolveAliases(args, aliases)
-			raws, err := buildActionWithDebug(compType, args, cloudID, webhookUser, webhookToken)
+			raws, err := buildActionWithDebug(compType, args, cloudID, webhookUser, webhookToken, debugPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			whenArgs, err := typesMapToStringMap(ctx, comp.When)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			raws, err = attachWhen(raws, resolveAliases(whenArgs, aliases))
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
@@ -689,76 +1999,89 @@ func BuildComponentsJSON(components []componentModel, cloudID, webhookUser, webh
 
 // buildInnerAction builds one or more action JSONs from the innerActionModel.
 // For add_release_related_work with debug="true", multiple actions are returned.
-func buildInnerAction(action innerActionModel, cloudID, webhookUser, webhookToken string, ctx context.Context, aliases map[string]string) ([]json.RawMessage, error) {
+func buildInnerAction(action innerActionModel, cloudID, webhookUser, webhookToken, debugPrefix string, ctx context.Context, aliases map[string]string) ([]json.RawMessage, error) {
 	actionType := action.Type.ValueString()
 	args, err := typesMapToStringMap(ctx, action.Args)
 	if err != nil {
 		return nil, err
 	}
 	args = resolveAliases(args, aliases)
-	return buildActionWithDebug(actionType, args, cloudID, webhookUser, webhookToken)
+	raws, err := buildActionWithDebug(actionType, args, cloudID, webhookUser, webhookToken, debugPrefix)
+	if err != nil {
+		return nil, err
+	}
+	whenArgs, err := typesMapToStringMap(ctx, action.When)
+	if err != nil {
+		return nil, err
+	}
+	return attachWhen(raws, resolveAliases(whenArgs, aliases))
 }
 
 // ParseComponents parses the full API components JSON back into structured componentModels.
 // It detects debug log actions at the top level and sets debug="true" on the following action.
 // reverse maps field IDs → alias names; pass nil for no alias resolution.
-func ParseComponents(raws []json.RawMessage, ctx context.Context, reverse map[string]string) ([]componentModel, error) {
+// debugPrefix is the prefix the debug logs were generated with.
+func ParseComponents(raws []json.RawMessage, ctx context.Context, reverse map[string]string, debugPrefix string) ([]componentModel, error) {
 	var result []componentModel
-	sawDebugLog := false
 
-	for i, raw := range raws {
+	for i := 0; i < len(raws); i++ {
+		// Detect and skip debug log actions.
+		sawDebugLog := isDebugLogRun(raws, i, debugPrefix)
+		if sawDebugLog {
+			i += debugLogCount
+		}
+		raw := raws[i]
+
 		var envelope struct {
-			Type string `json:"type"`
+			Component string `json:"component"`
+			Type      string `json:"type"`
 		}
 		if err := json.Unmarshal(raw, &envelope); err != nil {
 			return nil, fmt.Errorf("component %d: parsing type: %w", i, err)
 		}
 
-		// Detect and skip debug log actions.
-		if envelope.Type == "codebarrel.action.log" {
-			var logAction struct {
-				Value string `json:"value"`
-			}
-			if err := json.Unmarshal(raw, &logAction); err != nil {
-				return nil, fmt.Errorf("component %d: parsing log value: %w", i, err)
-			}
-			if strings.HasPrefix(logAction.Value, debugLogPrefix) {
-				sawDebugLog = true
-				continue
-			}
-		}
-
 		if envelope.Type == "jira.condition.container.block" {
-			sawDebugLog = false
-			model, err := parseConditionContainer(raw, ctx, reverse)
+			model, err := parseConditionContainer(raw, ctx, reverse, debugPrefix)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			result = append(result, *model)
+		} else if envelope.Component == "BRANCH" {
+			model, err := parseBranch(raw, ctx, reverse, debugPrefix)
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
 			result = append(result, *model)
 		} else {
+			// Unknown API types are kept verbatim as raw components, so
+			// one unsupported action doesn't force the rule into components_json.
 			userType, ok := apiTypeToComponentUserType[envelope.Type]
 			if !ok {
-				return nil, fmt.Errorf("component %d: unrecognized API type %q", i, envelope.Type)
+				userType = rawComponentType
 			}
-			def := componentRegistry[userType]
-			args, err := def.parse(raw)
+			userType, args, err := parseAs(userType, raw)
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
 
-			if sawDebugLog && userType == "add_release_related_work" {
+			if sawDebugLog {
 				args["debug"] = "true"
 			}
-			sawDebugLog = false
 
 			args = unresolveAliases(args, reverse)
 			argsMap, err := stringMapToTypesMap(ctx, args)
 			if err != nil {
 				return nil, fmt.Errorf("component %d: %w", i, err)
 			}
+			when, err := parseWhen(raw, reverse)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
 			result = append(result, componentModel{
+				Key:  types.StringNull(),
 				Type: types.StringValue(userType),
 				Args: argsMap,
+				When: when,
 			})
 		}
 	}
@@ -766,6 +2089,35 @@ func ParseComponents(raws []json.RawMessage, ctx context.Context, reverse map[st
 	return result, nil
 }
 
+// ParseRuleComponents parses rule's components exactly as the rule resource
+// reads them, for callers outside Terraform such as import-gen or tests.
+// reverse and debugPrefix are as for ParseComponents; pass the client's
+// ReverseAliases and DebugLogPrefix to match the provider's output.
+func ParseRuleComponents(rule *client.Rule, reverse map[string]string, debugPrefix string) ([]componentModel, error) {
+	return ParseComponents(rule.Components, context.Background(), reverse, debugPrefix)
+}
+
+// StructuredFallbackReasons reports why a rule's trigger or components can't
+// be expressed with the structured trigger/components blocks, one entry per
+// failing trigger or top-level component (e.g. an unrecognized API type). An
+// empty result means the whole rule parses into structured form.
+func StructuredFallbackReasons(trigger json.RawMessage, components []json.RawMessage, ctx context.Context, debugPrefix string) []string {
+	var reasons []string
+	if _, _, err := ParseTrigger(trigger); err != nil {
+		reasons = append(reasons, fmt.Sprintf("trigger: %s", err))
+	} else if _, err := parseWhen(trigger, nil); err != nil {
+		reasons = append(reasons, fmt.Sprintf("trigger: %s", err))
+	}
+	// Parse components one at a time so one failure doesn't hide the rest.
+	// Debug log runs then parse as plain log actions, which is fine here.
+	for i, raw := range components {
+		if _, err := ParseComponents([]json.RawMessage{raw}, ctx, nil, debugPrefix); err != nil {
+			reasons = append(reasons, fmt.Sprintf("component %d: %s", i, strings.TrimPrefix(err.Error(), "component 0: ")))
+		}
+	}
+	return reasons
+}
+
 // --- Helper functions ---
 
 func typesMapToStringMap(ctx context.Context, m types.Map) (map[string]string, error) {
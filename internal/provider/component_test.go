@@ -1,9 +1,20 @@
 package provider
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func TestResolveAliases(t *testing.T) {
@@ -34,6 +45,74 @@ func TestResolveAliases(t *testing.T) {
 	}
 }
 
+func TestResolveAliases_MethodChains(t *testing.T) {
+	aliases := map[string]string{
+		"release_version": "customfield_10709",
+		"sprint_name":     "customfield_10020.name",
+	}
+	reverse := map[string]string{
+		"customfield_10709":      "release_version",
+		"customfield_10020.name": "sprint_name",
+	}
+
+	cases := []struct {
+		name, in, want string
+	}{
+		{
+			"format tail as in add_release_related_work",
+			`https://api.atlassian.com/ex/jira/c/rest/api/3/version/{{issue.release_version.format("###")}}/relatedwork`,
+			`https://api.atlassian.com/ex/jira/c/rest/api/3/version/{{issue.customfield_10709.format("###")}}/relatedwork`,
+		},
+		{
+			"chained methods on trigger issue",
+			`{{triggerIssue.release_version.name.toLowerCase().replace("v", "")}}`,
+			`{{triggerIssue.customfield_10709.name.toLowerCase().replace("v", "")}}`,
+		},
+		{
+			"alias mapping to dotted path",
+			`{{issue.sprint_name.abbreviate(10)}}`,
+			`{{issue.customfield_10020.name.abbreviate(10)}}`,
+		},
+		{
+			"longer field name is not an alias",
+			`{{issue.release_versions}} {{issue.release_version}}`,
+			`{{issue.release_versions}} {{issue.customfield_10709}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resolveAliases(map[string]string{"v": tc.in}, aliases)["v"]
+			if got != tc.want {
+				t.Fatalf("resolve: got %q, want %q", got, tc.want)
+			}
+			back := unresolveAliases(map[string]string{"v": got}, reverse)["v"]
+			if back != tc.in {
+				t.Errorf("unresolve: got %q, want %q", back, tc.in)
+			}
+		})
+	}
+}
+
+func TestResolveAliases_SinglePass(t *testing.T) {
+	// An alias whose field ID is another alias's name must not be replaced twice.
+	aliases := map[string]string{"a": "b", "b": "c", "b.name": "d"}
+
+	cases := []struct{ in, want string }{
+		{"{{issue.a}} {{issue.b}}", "{{issue.b}} {{issue.c}}"},
+		{"{{issue.b.name.abbreviate(3)}} {{triggerIssue.b.key}}", "{{issue.d.abbreviate(3)}} {{triggerIssue.c.key}}"},
+		{"{{issue.ab}} {{issue.a-b}} {{issue.}} {{fieldChange.a}}", "{{issue.ab}} {{issue.a-b}} {{issue.}} {{fieldChange.a}}"},
+		{"{{issue.a", "{{issue.b"},
+		{"a", "b"},
+		{"b.name", "d"},
+	}
+	for _, tc := range cases {
+		if got := resolveAliases(map[string]string{"v": tc.in}, aliases)["v"]; got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
 func TestResolveAliases_Empty(t *testing.T) {
 	args := map[string]string{"key": "value"}
 
@@ -71,6 +150,54 @@ func TestUnresolveAliases(t *testing.T) {
 	}
 }
 
+func TestResolveAliasesInJSON(t *testing.T) {
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	reverse := map[string]string{"customfield_10709": "release_version"}
+	raw := json.RawMessage(`{"value":{"message":"{{issue.release_version}}","fields":["release_version","summary"],"release_version":1,"n":12345678901234567890}}`)
+
+	got, err := resolveAliasesInJSON(raw, aliases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"value":{"fields":["customfield_10709","summary"],"message":"{{issue.customfield_10709}}","n":12345678901234567890,"release_version":1}}`
+	if string(got) != want {
+		t.Errorf("resolved:\n got %s\nwant %s", got, want)
+	}
+
+	back, err := unresolveAliasesInJSON(got, reverse)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(back), `"message":"{{issue.release_version}}"`) || !strings.Contains(string(back), `["release_version","summary"]`) {
+		t.Errorf("unresolved: got %s", back)
+	}
+
+	// Without aliases the input is returned untouched, even if it isn't JSON.
+	if got, err := resolveAliasesInJSON(json.RawMessage("not json"), nil); err != nil || string(got) != "not json" {
+		t.Errorf("no aliases: got %s, %v", got, err)
+	}
+}
+
+func TestResolveAliases_Concurrent(t *testing.T) {
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	reverse := map[string]string{"customfield_10709": "release_version"}
+	args := map[string]string{"url": "{{issue.release_version}}"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resolved := resolveAliases(args, aliases)
+			back := unresolveAliases(resolved, reverse)
+			if back["url"] != args["url"] {
+				t.Errorf("round-trip: got %q, want %q", back["url"], args["url"])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestBuildLog(t *testing.T) {
 	raw, err := buildLog(map[string]string{"message": "hello world"}, "", "", "")
 	if err != nil {
@@ -103,6 +230,51 @@ func TestBuildLog_MissingMessage(t *testing.T) {
 	}
 }
 
+func TestLogLevel_RoundTrip(t *testing.T) {
+	raw, err := buildLog(map[string]string{"message": "checking version", "level": "debug"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	var action struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if action.Value != "[DEBUG] checking version" {
+		t.Errorf("value: got %q, want %q", action.Value, "[DEBUG] checking version")
+	}
+
+	args, err := parseLog(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if args["level"] != "debug" || args["message"] != "checking version" {
+		t.Errorf("parsed args: got %v", args)
+	}
+
+	if _, err := buildLog(map[string]string{"message": "x", "level": "verbose"}, "", "", ""); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestLogLevel_NotDetectedAsDebugLog(t *testing.T) {
+	// A user log at debug level must survive parsing rather than being taken
+	// for an auto-generated add_release_related_work debug log.
+	raw, err := buildLog(map[string]string{"message": "add_release_related_work] manual", "level": "debug"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Type.ValueString() != "log" {
+		t.Fatalf("expected the log to be kept, got %#v", parsed)
+	}
+}
+
 func TestBuildComment(t *testing.T) {
 	raw, err := buildComment(map[string]string{"message": "a comment"}, "", "", "")
 	if err != nil {
@@ -292,6 +464,250 @@ func TestParseComment_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestComment_ADF(t *testing.T) {
+	// Formatted input comes back in jsonencode form: compact, sorted keys.
+	msg := `{"version": 1, "type": "doc", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "{{issue.key}} shipped"}]}]}`
+	want := `{"content":[{"content":[{"text":"{{issue.key}} shipped","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`
+
+	raw, err := buildComment(map[string]string{"message": msg, "body_format": "adf"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	var action struct {
+		Value struct {
+			Comment json.RawMessage `json:"comment"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if string(action.Value.Comment) != want {
+		t.Errorf("comment: got %s, want %s", action.Value.Comment, want)
+	}
+
+	args, err := parseComment(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if args["body_format"] != "adf" || args["message"] != want {
+		t.Errorf("parsed args: got %v", args)
+	}
+
+	for _, bad := range []map[string]string{
+		{"message": "plain text", "body_format": "adf"},
+		{"message": `{"type":"paragraph"}`, "body_format": "adf"},
+		{"message": "hi", "body_format": "html"},
+	} {
+		if _, err := buildComment(bad, "", "", ""); err == nil {
+			t.Errorf("%v: expected error", bad)
+		}
+	}
+
+	// Wiki (the default) omits body_format on read.
+	raw, _ = buildComment(map[string]string{"message": "hi", "body_format": "wiki"}, "", "", "")
+	if args, _ := parseComment(raw); len(args) != 1 || args["message"] != "hi" {
+		t.Errorf("wiki args: got %v", args)
+	}
+}
+
+func TestComment_Visibility(t *testing.T) {
+	args := map[string]string{"message": "internal note", "visibility_type": "ROLE", "visibility_value": "Service Desk Team"}
+	raw, err := buildComment(args, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if !strings.Contains(string(raw), `"commentVisibility":{"type":"ROLE","value":"Service Desk Team"}`) {
+		t.Errorf("expected commentVisibility in %s", raw)
+	}
+	parsed, err := parseComment(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !maps.Equal(parsed, args) {
+		t.Errorf("args: got %v, want %v", parsed, args)
+	}
+
+	for _, bad := range []map[string]string{
+		{"message": "x", "visibility_type": "ROLE"},
+		{"message": "x", "visibility_value": "Developers"},
+		{"message": "x", "visibility_type": "role", "visibility_value": "Developers"},
+	} {
+		if _, err := buildComment(bad, "", "", ""); err == nil {
+			t.Errorf("%v: expected error", bad)
+		}
+	}
+}
+
+func TestParseComponents_CommentShapes(t *testing.T) {
+	comment := func(value string) json.RawMessage {
+		return json.RawMessage(`{"component":"ACTION","type":"jira.issue.comment","schemaVersion":3,"value":{"comment":` + value + `}}`)
+	}
+	tests := []struct {
+		name     string
+		value    string
+		wantType string
+		wantArgs map[string]string
+	}{
+		{"wiki string", `"shipped"`, "comment", map[string]string{"message": "shipped"}},
+		{"ADF document", `{"type":"doc","version":1,"content":[]}`, "comment",
+			map[string]string{"message": `{"content":[],"type":"doc","version":1}`, "body_format": "adf"}},
+		{"missing", `null`, "comment", map[string]string{"message": ""}},
+		{"unknown object", `{"format":"markdown","text":"shipped"}`, rawComponentType, nil},
+		{"array", `["shipped"]`, rawComponentType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := comment(tt.value)
+			parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if got := parsed[0].Type.ValueString(); got != tt.wantType {
+				t.Fatalf("type: got %q, want %q", got, tt.wantType)
+			}
+			args, _ := typesMapToStringMap(context.Background(), parsed[0].Args)
+			if tt.wantType == rawComponentType {
+				got, _ := normalizeRawJSON(json.RawMessage(args["json"]))
+				want, _ := normalizeRawJSON(raw)
+				if got != want {
+					t.Errorf("json: got %s, want %s", args["json"], raw)
+				}
+				return
+			}
+			if !maps.Equal(args, tt.wantArgs) {
+				t.Errorf("args: got %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	// Nested under a condition, an unknown shape falls back the same way.
+	actions, err := parseInnerActions([]json.RawMessage{comment(`42`)}, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("inner parse error: %v", err)
+	}
+	if got := actions[0].Type.ValueString(); got != rawComponentType {
+		t.Errorf("inner type: got %q, want %q", got, rawComponentType)
+	}
+}
+
+func TestParseSetProperty_RoundTrip(t *testing.T) {
+	value := `{"release":"{{issue.release_version}}","tags":["a","b"]}`
+	args := resolveAliases(map[string]string{"key": "sync.state", "value": value},
+		map[string]string{"release_version": "customfield_10709"})
+
+	raw, err := buildSetProperty(args, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if !strings.Contains(string(raw), "customfield_10709") {
+		t.Errorf("expected alias resolved inside value, got %s", raw)
+	}
+
+	parsed, err := parseSetProperty(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	parsed = unresolveAliases(parsed, map[string]string{"customfield_10709": "release_version"})
+
+	if parsed["key"] != "sync.state" {
+		t.Errorf("key: got %q, want %q", parsed["key"], "sync.state")
+	}
+	if parsed["value"] != value {
+		t.Errorf("value: got %q, want %q", parsed["value"], value)
+	}
+}
+
+func TestParseAssignIssue_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]string
+		wantJSON string
+	}{
+		{"unassign", map[string]string{}, `"value":{"assignType":"UNASSIGN"}`},
+		{"account ID", map[string]string{"assignee": "5b10ac8d82e05b22cc7d4ef5"},
+			`"assignee":{"type":"ID","value":"5b10ac8d82e05b22cc7d4ef5"}`},
+		{"smart value", map[string]string{"assignee": "{{reporter.accountId}}"},
+			`"assignee":{"type":"SMART","value":"{{reporter.accountId}}"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := buildAssignIssue(tt.args, "", "", "")
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			if !strings.Contains(string(raw), tt.wantJSON) {
+				t.Errorf("expected %s in %s", tt.wantJSON, raw)
+			}
+			parsed, err := parseAssignIssue(raw)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if !maps.Equal(parsed, tt.args) {
+				t.Errorf("args: got %v, want %v", parsed, tt.args)
+			}
+		})
+	}
+
+	// An empty assignee unassigns, like omitting it.
+	raw, _ := buildAssignIssue(map[string]string{"assignee": ""}, "", "", "")
+	if !strings.Contains(string(raw), `"UNASSIGN"`) {
+		t.Errorf("empty assignee: got %s", raw)
+	}
+
+	// Assign types the component can't express are read as raw.
+	raw = json.RawMessage(`{"component":"ACTION","type":"jira.issue.assign","schemaVersion":5,"value":{"assignType":"AUTOMATIC"}}`)
+	parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if got := parsed[0].Type.ValueString(); got != rawComponentType {
+		t.Errorf("automatic: got type %q, want %q", got, rawComponentType)
+	}
+}
+
+func TestParseSetProperty_NonStringValues(t *testing.T) {
+	for _, value := range []string{`42`, `12345678901234567890`, `1.5`, `true`, `{"count":3,"tags":["a"]}`, `[1,2]`} {
+		t.Run(value, func(t *testing.T) {
+			args := map[string]string{"key": "sync.count", "value_json": value}
+			raw, err := buildSetProperty(args, "", "", "")
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			if want := `"value":` + value; !strings.Contains(string(raw), want) {
+				t.Errorf("expected %s in %s", want, raw)
+			}
+			parsed, err := parseSetProperty(raw)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if !maps.Equal(parsed, args) {
+				t.Errorf("args: got %v, want %v", parsed, args)
+			}
+		})
+	}
+
+	// The API's own numeric value, as stored by a rule edited in Jira.
+	parsed, err := parseSetProperty(json.RawMessage(`{"type":"jira.set.entity.property","value":{"entityType":"ISSUE","key":"k","value":7}}`))
+	if err != nil || parsed["value_json"] != "7" {
+		t.Errorf("numeric value: got %v, %v", parsed, err)
+	}
+
+	for _, args := range []map[string]string{
+		{"key": "k", "value": "x", "value_json": "1"},
+		{"key": "k", "value_json": "{nope"},
+	} {
+		if _, err := buildSetProperty(args, "", "", ""); err == nil {
+			t.Errorf("args %v: expected an error", args)
+		}
+	}
+}
+
+func TestBuildSetProperty_MissingKey(t *testing.T) {
+	if _, err := buildSetProperty(map[string]string{"value": "x"}, "", "", ""); err == nil {
+		t.Fatal("expected error for missing key")
+	}
+}
+
 func TestParseAddReleaseRelatedWork_RoundTrip(t *testing.T) {
 	args := map[string]string{
 		"version_field": "customfield_10709",
@@ -317,6 +733,139 @@ func TestParseAddReleaseRelatedWork_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestAddReleaseRelatedWork_ContentType(t *testing.T) {
+	base := map[string]string{
+		"version_field": "customfield_10709",
+		"category":      "other",
+		"title":         "Deploy",
+		"url":           "https://example.com",
+	}
+	withCT := func(ct string) map[string]string {
+		args := map[string]string{}
+		for k, v := range base {
+			args[k] = v
+		}
+		if ct != "" {
+			args["content_type"] = ct
+		}
+		return args
+	}
+
+	cases := []struct {
+		in, wantAPI, wantParsed string
+	}{
+		{"", "custom", ""},
+		{"application/json", "application/json", "application/json"},
+	}
+	for _, tc := range cases {
+		raw, err := buildAddReleaseRelatedWork(withCT(tc.in), "cloud-123", "user@test.com", "token123")
+		if err != nil {
+			t.Fatalf("content_type %q: build error: %v", tc.in, err)
+		}
+		var action struct {
+			Value struct {
+				ContentType string `json:"contentType"`
+			} `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &action); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if action.Value.ContentType != tc.wantAPI {
+			t.Errorf("content_type %q: API contentType got %q, want %q", tc.in, action.Value.ContentType, tc.wantAPI)
+		}
+		args, err := parseAddReleaseRelatedWork(raw)
+		if err != nil {
+			t.Fatalf("parse error: %v", err)
+		}
+		if args["content_type"] != tc.wantParsed {
+			t.Errorf("content_type %q: parsed got %q, want %q", tc.in, args["content_type"], tc.wantParsed)
+		}
+	}
+
+	if _, err := buildAddReleaseRelatedWork(withCT("form"), "cloud-123", "user@test.com", "token123"); err == nil {
+		t.Error("expected error for unsupported content_type")
+	}
+}
+
+func TestAddReleaseRelatedWork_ErrorAndResponseFlags(t *testing.T) {
+	args := map[string]string{
+		"version_field":     "customfield_10709",
+		"category":          "other",
+		"title":             "Deploy",
+		"url":               "https://example.com",
+		"continue_on_error": "true",
+		"response_enabled":  "true",
+	}
+
+	raw, err := buildAddReleaseRelatedWork(args, "cloud-123", "user@test.com", "token123")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	var action struct {
+		Value struct {
+			ContinueOnError bool `json:"continueOnErrorEnabled"`
+			ResponseEnabled bool `json:"responseEnabled"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !action.Value.ContinueOnError || !action.Value.ResponseEnabled {
+		t.Errorf("flags: got %+v, want both true", action.Value)
+	}
+
+	parsed, err := parseAddReleaseRelatedWork(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if parsed["continue_on_error"] != "true" || parsed["response_enabled"] != "true" {
+		t.Errorf("parsed flags: got %v", parsed)
+	}
+
+	// Defaults stay off and are not echoed back.
+	delete(args, "continue_on_error")
+	delete(args, "response_enabled")
+	raw, _ = buildAddReleaseRelatedWork(args, "cloud-123", "user@test.com", "token123")
+	parsed, _ = parseAddReleaseRelatedWork(raw)
+	if _, ok := parsed["continue_on_error"]; ok {
+		t.Errorf("continue_on_error should be omitted by default, got %v", parsed)
+	}
+
+	args["continue_on_error"] = "maybe"
+	if _, err := buildAddReleaseRelatedWork(args, "cloud-123", "user@test.com", "token123"); err == nil {
+		t.Error("expected error for non-boolean continue_on_error")
+	}
+}
+
+func TestAddReleaseRelatedWork_ContentTypeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, ct := range webhookContentTypes {
+		t.Run(ct, func(t *testing.T) {
+			want := map[string]string{
+				"version_field": "customfield_10709",
+				"category":      "other",
+				"title":         "Deploy",
+				"url":           "https://example.com",
+				"content_type":  ct,
+			}
+			args, _ := stringMapToTypesMap(ctx, want)
+			config := []componentModel{{Type: types.StringValue("add_release_related_work"), Args: args, When: types.MapNull(types.StringType)}}
+			raws, err := BuildComponentsJSON(config, "cloud-123", "user@test.com", "token123", client.DefaultDebugLogPrefix, ctx, nil)
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			parsed, err := ParseComponents(raws, ctx, nil, client.DefaultDebugLogPrefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			preserveDefaultArgs(ctx, config, parsed)
+			if got, _ := typesMapToStringMap(ctx, parsed[0].Args); !maps.Equal(got, want) {
+				t.Errorf("args: got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestBuildDebugLogs(t *testing.T) {
 	args := map[string]string{
 		"version_field": "customfield_10709",
@@ -325,7 +874,7 @@ func TestBuildDebugLogs(t *testing.T) {
 		"url":           "https://example.com",
 	}
 
-	logs, err := buildDebugLogs(args, "cloud-123")
+	logs, err := buildDebugLogs(args, "cloud-123", client.DefaultDebugLogPrefix)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -342,7 +891,7 @@ func TestBuildDebugLogs(t *testing.T) {
 		if err := json.Unmarshal(raw, &action); err != nil {
 			t.Fatalf("log %d: invalid JSON: %v", i, err)
 		}
-		if !strings.HasPrefix(action.Value, debugLogPrefix) {
+		if !strings.HasPrefix(action.Value, client.DefaultDebugLogPrefix) {
 			t.Errorf("log %d: missing debug prefix: %s", i, action.Value)
 		}
 	}
@@ -357,7 +906,7 @@ func TestBuildActionWithDebug_True(t *testing.T) {
 		"debug":         "true",
 	}
 
-	actions, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123")
+	actions, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123", client.DefaultDebugLogPrefix)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -400,7 +949,7 @@ func TestBuildActionWithDebug_False(t *testing.T) {
 		"url":           "https://example.com",
 	}
 
-	actions, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123")
+	actions, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123", client.DefaultDebugLogPrefix)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -419,3 +968,1055 @@ func TestBuildActionWithDebug_False(t *testing.T) {
 		t.Errorf("type: got %q, want %q", action.Type, "jira.issue.outgoing.webhook")
 	}
 }
+
+func TestParseComponents_DebugLogs(t *testing.T) {
+	args := map[string]string{
+		"version_field": "customfield_10709",
+		"category":      "other",
+		"title":         "Deploy",
+		"url":           "https://example.com",
+		"debug":         "true",
+	}
+
+	for _, prefix := range []string{client.DefaultDebugLogPrefix, "[tf-debug] "} {
+		t.Run(prefix, func(t *testing.T) {
+			raws, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123", prefix)
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+
+			parsed, err := ParseComponents(raws, context.Background(), nil, prefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if len(parsed) != 1 {
+				t.Fatalf("expected debug logs to fold into 1 component, got %d", len(parsed))
+			}
+			got, _ := typesMapToStringMap(context.Background(), parsed[0].Args)
+			if got["debug"] != "true" {
+				t.Errorf("debug: got %q, want %q", got["debug"], "true")
+			}
+		})
+	}
+}
+
+func TestParseComponents_NestedDebugWebhooks(t *testing.T) {
+	ctx := context.Background()
+	webhook := func(debug string, when map[string]string) innerActionModel {
+		m := map[string]string{
+			"version_field": "release_version",
+			"category":      "other",
+			"title":         "Deploy {{issue.release_version}}",
+			"url":           "https://example.com",
+		}
+		if debug != "" {
+			m["debug"] = debug
+		}
+		args, _ := stringMapToTypesMap(ctx, m)
+		w := types.MapNull(types.StringType)
+		if when != nil {
+			w, _ = stringMapToTypesMap(ctx, when)
+		}
+		return innerActionModel{Type: types.StringValue("add_release_related_work"), Args: args, When: w}
+	}
+	logArgs, _ := stringMapToTypesMap(ctx, map[string]string{"message": "before"})
+	logAction := innerActionModel{Type: types.StringValue("log"), Args: logArgs, When: types.MapNull(types.StringType)}
+	condArgs, _ := stringMapToTypesMap(ctx, map[string]string{"first": "a", "operator": "equals", "second": "a"})
+	branchArgs, _ := stringMapToTypesMap(ctx, map[string]string{"json": `{"component":"BRANCH","type":"jira.issue.related","value":{"relatedType":"sub-tasks"}}`})
+
+	components := []componentModel{
+		{
+			Type: types.StringValue("condition"),
+			Args: condArgs,
+			When: types.MapNull(types.StringType),
+			Then: []innerActionModel{webhook("true", nil), webhook("true", map[string]string{"jql": "project = X"})},
+			Else: []innerActionModel{logAction, webhook("true", nil), webhook("", nil)},
+		},
+		{
+			Type: types.StringValue(branchComponentType),
+			Args: branchArgs,
+			When: types.MapNull(types.StringType),
+			Then: []innerActionModel{webhook("true", nil)},
+		},
+	}
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	reverse := map[string]string{"customfield_10709": "release_version"}
+	raws, err := BuildComponentsJSON(components, "cloud-123", "user@test.com", "token123", client.DefaultDebugLogPrefix, ctx, aliases)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if n := strayDebugLogs(raws, client.DefaultDebugLogPrefix); n != 0 {
+		t.Errorf("stray debug logs: got %d, want 0", n)
+	}
+
+	parsed, err := ParseComponents(raws, ctx, reverse, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(parsed))
+	}
+	check := func(name string, got []innerActionModel, want []innerActionModel) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d actions, want %d: %v", name, len(got), len(want), got)
+		}
+		for i := range want {
+			g, _ := typesMapToStringMap(ctx, got[i].Args)
+			w, _ := typesMapToStringMap(ctx, want[i].Args)
+			if !got[i].Type.Equal(want[i].Type) || !maps.Equal(g, w) {
+				t.Errorf("%s[%d]: got %s %v, want %s %v", name, i, got[i].Type, g, want[i].Type, w)
+			}
+			if !got[i].When.Equal(want[i].When) {
+				t.Errorf("%s[%d] when: got %v, want %v", name, i, got[i].When, want[i].When)
+			}
+		}
+	}
+	check("condition then", parsed[0].Then, components[0].Then)
+	check("condition else", parsed[0].Else, components[0].Else)
+	check("branch then", parsed[1].Then, components[1].Then)
+}
+
+func TestParseComponents_IncompleteDebugLogs(t *testing.T) {
+	args := map[string]string{
+		"version_field": "customfield_10709",
+		"category":      "other",
+		"title":         "Deploy",
+		"url":           "https://example.com",
+		"debug":         "true",
+	}
+	raws, err := buildActionWithDebug("add_release_related_work", args, "cloud-123", "user@test.com", "token123", client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	note, _ := buildLog(map[string]string{"message": "note"}, "", "", "")
+	seq := func(idx ...int) []json.RawMessage {
+		var out []json.RawMessage
+		for _, i := range idx {
+			if i < 0 {
+				out = append(out, note)
+				continue
+			}
+			out = append(out, raws[i])
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		raws      []json.RawMessage
+		wantStray int
+	}{
+		{"complete", seq(0, 1, 2, 3, 4), 0},
+		{"reordered", seq(1, 0, 2, 3, 4), 4},
+		{"partial", seq(0, 1, 3, 4), 3},
+		{"not contiguous", seq(0, 1, -1, 2, 3, 4), 4},
+		{"not before the webhook", seq(0, 1, 2, 3, -1, 4), 4},
+		{"without webhook", seq(0, 1, 2, 3), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strayDebugLogs(tt.raws, client.DefaultDebugLogPrefix); got != tt.wantStray {
+				t.Errorf("stray debug logs: got %d, want %d", got, tt.wantStray)
+			}
+			parsed, err := ParseComponents(tt.raws, context.Background(), nil, client.DefaultDebugLogPrefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			// Only a complete run folds into the webhook; otherwise every log is kept.
+			wantLen := len(tt.raws)
+			if tt.wantStray == 0 {
+				wantLen = 1
+			}
+			if len(parsed) != wantLen {
+				t.Fatalf("got %d components, want %d", len(parsed), wantLen)
+			}
+			last := parsed[len(parsed)-1]
+			if last.Type.ValueString() != "add_release_related_work" {
+				return
+			}
+			got, _ := typesMapToStringMap(context.Background(), last.Args)
+			if _, debug := got["debug"]; debug != (tt.wantStray == 0) {
+				t.Errorf("debug: got %v", got)
+			}
+		})
+	}
+
+	// Stray logs inside a condition are counted too.
+	container, err := BuildConditionJSON(map[string]string{"first": "a", "operator": "equals"}, seq(0, 2, 3, 4), nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if got := strayDebugLogs([]json.RawMessage{container}, client.DefaultDebugLogPrefix); got != 3 {
+		t.Errorf("nested: got %d, want 3", got)
+	}
+}
+
+func TestParseRuleComponents(t *testing.T) {
+	logAction, err := buildLog(map[string]string{"message": "{{issue.customfield_10709}}"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	rule := &client.Rule{Components: []json.RawMessage{logAction}}
+
+	parsed, err := ParseRuleComponents(rule, map[string]string{"customfield_10709": "release_version"}, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Type.ValueString() != "log" {
+		t.Fatalf("got %+v, want one log component", parsed)
+	}
+	args, _ := typesMapToStringMap(context.Background(), parsed[0].Args)
+	if args["message"] != "{{issue.release_version}}" {
+		t.Errorf("message: got %q, want the alias resolved", args["message"])
+	}
+}
+
+func TestParseComponents_LogWithDebugPrefixIsKept(t *testing.T) {
+	userLog, err := buildLog(map[string]string{"message": client.DefaultDebugLogPrefix + "my own note"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	webhook, err := buildAddReleaseRelatedWork(map[string]string{
+		"version_field": "customfield_10709",
+		"category":      "other",
+		"title":         "Deploy",
+		"url":           "https://example.com",
+	}, "cloud-123", "user@test.com", "token123")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	parsed, err := ParseComponents([]json.RawMessage{userLog, webhook}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("expected the log and the webhook, got %d components", len(parsed))
+	}
+	logArgs, _ := typesMapToStringMap(context.Background(), parsed[0].Args)
+	if logArgs["message"] != client.DefaultDebugLogPrefix+"my own note" {
+		t.Errorf("log message: got %q", logArgs["message"])
+	}
+	webhookArgs, _ := typesMapToStringMap(context.Background(), parsed[1].Args)
+	if _, ok := webhookArgs["debug"]; ok {
+		t.Errorf("webhook should not be marked debug, got %v", webhookArgs)
+	}
+}
+
+func TestParseComponents_EmptyBranchesAreNil(t *testing.T) {
+	thenAction, _ := buildLog(map[string]string{"message": "then"}, "", "", "")
+	condArgs := map[string]string{"first": "{{issue.key}}", "operator": "equals", "second": "X"}
+
+	// Populated then, empty else.
+	raw, err := BuildConditionJSON(condArgs, []json.RawMessage{thenAction}, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed[0].Then) != 1 {
+		t.Errorf("then: got %d actions, want 1", len(parsed[0].Then))
+	}
+	if parsed[0].Else != nil {
+		t.Errorf("else: got %#v, want nil", parsed[0].Else)
+	}
+
+	// Empty then, empty else.
+	raw, err = BuildConditionJSON(condArgs, nil, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	parsed, err = ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if parsed[0].Then != nil {
+		t.Errorf("then: got %#v, want nil", parsed[0].Then)
+	}
+}
+
+func TestParseComponents_ElseIfRejected(t *testing.T) {
+	block := func(conditions string) string {
+		return `{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[],"conditions":[` + conditions + `]}`
+	}
+	comparator := `{"component":"CONDITION","type":"jira.comparator.condition","value":{"first":"a","operator":"EQUALS","second":"b"}}`
+	container := func(blocks ...string) json.RawMessage {
+		return json.RawMessage(`{"component":"CONDITION","type":"jira.condition.container.block","children":[` + strings.Join(blocks, ",") + `]}`)
+	}
+
+	cases := map[string]json.RawMessage{
+		"if, else-if, else": container(block(comparator), block(comparator), block("")),
+		"if, else-if":       container(block(comparator), block(comparator)),
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+			if err == nil || !strings.Contains(err.Error(), "else-if") {
+				t.Errorf("expected else-if error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUserCondition_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	logArgs, _ := stringMapToTypesMap(ctx, map[string]string{"message": "admin"})
+
+	tests := []struct {
+		name string
+		args map[string]string
+		want string
+	}{
+		{
+			name: "initiator",
+			args: map[string]string{"check": "in_group", "value": "jira-admins"},
+			want: `{"conditions":[{"check":"IN_GROUP","criteria":["jira-admins"],"field":"initiator"}],"operator":"AND"}`,
+		},
+		{
+			name: "explicit user",
+			args: map[string]string{"check": "not_in_group", "value": "contractors", "user": "assignee"},
+			want: `{"conditions":[{"check":"NOT_IN_GROUP","criteria":["contractors"],"field":"assignee"}],"operator":"AND"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, _ := stringMapToTypesMap(ctx, tt.args)
+			components := []componentModel{{
+				Type: types.StringValue("user_condition"),
+				Args: args,
+				When: types.MapNull(types.StringType),
+				Then: []innerActionModel{{Type: types.StringValue("log"), Args: logArgs, When: types.MapNull(types.StringType)}},
+			}}
+			raws, err := BuildComponentsJSON(components, "", "", "", client.DefaultDebugLogPrefix, ctx, nil)
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			want := `"type":"jira.user.condition","value":` + tt.want
+			if !strings.Contains(string(raws[0]), want) {
+				t.Errorf("expected %s in %s", want, raws[0])
+			}
+
+			parsed, err := ParseComponents(raws, ctx, nil, client.DefaultDebugLogPrefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if got := parsed[0].Type.ValueString(); got != "user_condition" {
+				t.Errorf("type: got %q, want user_condition", got)
+			}
+			got, _ := typesMapToStringMap(ctx, parsed[0].Args)
+			if !maps.Equal(got, tt.args) {
+				t.Errorf("args: got %v, want %v", got, tt.args)
+			}
+			if len(parsed[0].Then) != 1 {
+				t.Errorf("then: got %d actions, want 1", len(parsed[0].Then))
+			}
+		})
+	}
+}
+
+func TestUserCondition_InvalidArgs(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]struct {
+		args    map[string]string
+		wantErr string
+	}{
+		"missing value": {map[string]string{"check": "in_group"}, "'value'"},
+		"unknown check": {map[string]string{"check": "in_role", "value": "Developers"}, "user_is, user_is_not, in_group, not_in_group"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args, _ := stringMapToTypesMap(ctx, tc.args)
+			components := []componentModel{{Type: types.StringValue("user_condition"), Args: args, When: types.MapNull(types.StringType)}}
+			_, err := BuildComponentsJSON(components, "", "", "", client.DefaultDebugLogPrefix, ctx, nil)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %s, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCommentCondition_ReadsAsConditionJSON(t *testing.T) {
+	// jira.comment.condition has no structured type, so it imports as a
+	// condition with condition_json and builds back to the same IF block.
+	ctx := context.Background()
+	cond := `{"component":"CONDITION","type":"jira.comment.condition","value":{"check":"IS_INTERNAL"}}`
+	raw := json.RawMessage(`{"component":"CONDITION","type":"jira.condition.container.block","children":[` +
+		`{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[],"conditions":[` + cond + `]}]}`)
+	parsed, err := ParseComponents([]json.RawMessage{raw}, ctx, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	args, _ := typesMapToStringMap(ctx, parsed[0].Args)
+	if parsed[0].Type.ValueString() != "condition" || args["condition_json"] == "" {
+		t.Fatalf("got %s %v, want a condition with condition_json", parsed[0].Type, args)
+	}
+
+	rebuilt, err := BuildComponentsJSON(parsed, "", "", "", client.DefaultDebugLogPrefix, ctx, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if !strings.Contains(string(rebuilt[0]), `"type":"jira.comment.condition","value":{"check":"IS_INTERNAL"}`) {
+		t.Errorf("comment condition not kept: %s", rebuilt[0])
+	}
+}
+
+func TestUserCondition_SeveralChecksReadAsConditionJSON(t *testing.T) {
+	check := `{"field":"reporter","check":"USER_IS","criteria":["a"]}`
+	cond := `{"component":"CONDITION","type":"jira.user.condition","value":{"conditions":[` + check + `,` + check + `],"operator":"OR"}}`
+	raw := json.RawMessage(`{"component":"CONDITION","type":"jira.condition.container.block","children":[` +
+		`{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[],"conditions":[` + cond + `]}]}`)
+	parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	want, _ := normalizeRawJSON(json.RawMessage(cond))
+	args, _ := typesMapToStringMap(context.Background(), parsed[0].Args)
+	if parsed[0].Type.ValueString() != "condition" || !maps.Equal(args, map[string]string{"condition_json": want}) {
+		t.Errorf("got %s %v, want condition_json %s", parsed[0].Type, args, want)
+	}
+}
+
+func TestConditionJSON_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	jql := `{"component":"CONDITION","type":"jira.jql.condition","schemaVersion":1,"value":{"jql":"project = X"}}`
+	thenArgs, _ := stringMapToTypesMap(ctx, map[string]string{"message": "in X"})
+	elseArgs, _ := stringMapToTypesMap(ctx, map[string]string{"message": "elsewhere"})
+	args, _ := stringMapToTypesMap(ctx, map[string]string{"condition_json": jql})
+	components := []componentModel{{
+		Type: types.StringValue("condition"),
+		Args: args,
+		When: types.MapNull(types.StringType),
+		Then: []innerActionModel{{Type: types.StringValue("log"), Args: thenArgs, When: types.MapNull(types.StringType)}},
+		Else: []innerActionModel{{Type: types.StringValue("log"), Args: elseArgs, When: types.MapNull(types.StringType)}},
+	}}
+	raws, err := BuildComponentsJSON(components, "", "", "", client.DefaultDebugLogPrefix, ctx, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if !strings.Contains(string(raws[0]), `"conditions":[{"component":"CONDITION","schemaVersion":1,"type":"jira.jql.condition","value":{"jql":"project = X"}}]`) {
+		t.Errorf("expected the condition verbatim in the IF block, got %s", raws[0])
+	}
+
+	// The API adds an id; the prior arg is kept since it's equivalent.
+	echo := strings.Replace(string(raws[0]), `"component":"CONDITION","schemaVersion":1,"type":"jira.jql.condition"`,
+		`"component":"CONDITION","id":"42","schemaVersion":1,"type":"jira.jql.condition"`, 1)
+	parsed, err := ParseComponents([]json.RawMessage{json.RawMessage(echo)}, ctx, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if parsed[0].Type.ValueString() != "condition" || len(parsed[0].Then) != 1 || len(parsed[0].Else) != 1 {
+		t.Fatalf("got %+v, want a condition with then and else", parsed[0])
+	}
+	preserveRawJSON(components, parsed)
+	if !parsed[0].Args.Equal(args) {
+		t.Errorf("args: got %v, want the configured condition_json", parsed[0].Args)
+	}
+
+	// Comparators still read back as first/operator/second.
+	cmp, _ := BuildConditionJSON(map[string]string{"first": "a", "operator": "equals", "second": "b"}, nil, nil)
+	parsed, _ = ParseComponents([]json.RawMessage{cmp}, ctx, nil, client.DefaultDebugLogPrefix)
+	if got, _ := typesMapToStringMap(ctx, parsed[0].Args); got["first"] != "a" {
+		t.Errorf("comparator args: got %v", got)
+	}
+}
+
+func TestConditionJSON_InvalidArgs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		args    map[string]string
+		wantErr string
+	}{
+		"with comparator args": {map[string]string{"condition_json": `{"component":"CONDITION","type":"x"}`, "first": "a"}, "no other args"},
+		"not an object":        {map[string]string{"condition_json": `[]`}, "JSON object"},
+		"not a condition":      {map[string]string{"condition_json": `{"component":"ACTION","type":"x"}`}, `"CONDITION"`},
+		"no type":              {map[string]string{"condition_json": `{"component":"CONDITION"}`}, `"type"`},
+		"neither":              {map[string]string{"second": "b"}, "'condition_json'"},
+		"bad match_type":       {map[string]string{"first": "a", "operator": "equals", "match_type": "some"}, "match_type"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := BuildConditionJSON(tc.args, nil, nil); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %s, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConditionMatchType_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for name, build := range map[string]func(map[string]string) (json.RawMessage, error){
+		"condition": func(m map[string]string) (json.RawMessage, error) {
+			m["first"], m["operator"] = "a", "equals"
+			return BuildConditionJSON(m, nil, nil)
+		},
+		"condition_json": func(m map[string]string) (json.RawMessage, error) {
+			m["condition_json"] = `{"component":"CONDITION","type":"jira.jql.condition","value":{"jql":"x"}}`
+			return BuildConditionJSON(m, nil, nil)
+		},
+		"user_condition": func(m map[string]string) (json.RawMessage, error) {
+			m["check"], m["value"] = "user_is", "acct-1"
+			return BuildUserConditionJSON(m, nil, nil)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			raw, err := build(map[string]string{"match_type": "any"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(string(raw), `"conditionMatchType":"ANY"`) {
+				t.Errorf("IF block should match ANY, got %s", raw)
+			}
+			parsed, err := ParseComponents([]json.RawMessage{raw}, ctx, nil, client.DefaultDebugLogPrefix)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if got, _ := typesMapToStringMap(ctx, parsed[0].Args); got["match_type"] != "any" {
+				t.Errorf("match_type: got %v, want any", got)
+			}
+
+			// ALL is the default and doesn't read back as an arg.
+			raw, _ = build(map[string]string{})
+			parsed, _ = ParseComponents([]json.RawMessage{raw}, ctx, nil, client.DefaultDebugLogPrefix)
+			if got, _ := typesMapToStringMap(ctx, parsed[0].Args); got["match_type"] != "" {
+				t.Errorf("default match type: got match_type %q, want none", got["match_type"])
+			}
+		})
+	}
+}
+
+func TestParseConditionContainer_SeveralConditions(t *testing.T) {
+	raw, _ := BuildConditionJSON(map[string]string{"first": "a", "operator": "equals", "match_type": "any"}, nil, nil)
+	var container map[string]interface{}
+	json.Unmarshal(raw, &container)
+	ifBlock := container["children"].([]interface{})[0].(map[string]interface{})
+	conds := ifBlock["conditions"].([]interface{})
+	ifBlock["conditions"] = append(conds, conds[0])
+	raw, _ = json.Marshal(container)
+
+	_, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err == nil || !strings.Contains(err.Error(), "2 conditions") {
+		t.Errorf("expected an error about 2 conditions, got %v", err)
+	}
+}
+
+func TestParseComponents_UnknownTypesAreRaw(t *testing.T) {
+	ctx := context.Background()
+	logRaw, _ := buildLog(map[string]string{"message": "hi"}, "", "", "")
+	assign := json.RawMessage(`{"id":"7","component":"ACTION","type":"jira.issue.assign","schemaVersion":5,"children":[],"conditions":[],"value":{"assignType":"SPECIFY_USER"}}`)
+	otherWebhook := json.RawMessage(`{"component":"ACTION","type":"jira.issue.outgoing.webhook","value":{"url":"https://example.com/hook"}}`)
+	thenLog, _ := buildLog(map[string]string{"message": "then"}, "", "", "")
+	cond, err := BuildConditionJSON(map[string]string{"first": "a", "operator": "equals", "second": "b"}, []json.RawMessage{thenLog, otherWebhook}, nil)
+	if err != nil {
+		t.Fatalf("build condition: %v", err)
+	}
+
+	parsed, err := ParseComponents([]json.RawMessage{logRaw, assign, cond}, ctx, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if got := parsed[0].Type.ValueString(); got != "log" {
+		t.Errorf("component 0 type: got %q, want log", got)
+	}
+	if got := parsed[1].Type.ValueString(); got != "raw" {
+		t.Fatalf("component 1 type: got %q, want raw", got)
+	}
+	args, _ := typesMapToStringMap(ctx, parsed[1].Args)
+	if want := `{"component":"ACTION","schemaVersion":5,"type":"jira.issue.assign","value":{"assignType":"SPECIFY_USER"}}`; args["json"] != want {
+		t.Errorf("raw json:\n got %s\nwant %s", args["json"], want)
+	}
+	if len(parsed[2].Then) != 2 || parsed[2].Then[1].Type.ValueString() != "raw" {
+		t.Errorf("then: got %v, want log then raw webhook", parsed[2].Then)
+	}
+
+	// Building the parsed model sends the raw components back unchanged.
+	raws, err := BuildComponentsJSON(parsed, "", "", "", client.DefaultDebugLogPrefix, ctx, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if string(raws[1]) != args["json"] {
+		t.Errorf("rebuilt raw: got %s, want %s", raws[1], args["json"])
+	}
+}
+
+func TestParseComponents_Branch(t *testing.T) {
+	ctx := context.Background()
+	assign := `{"component":"ACTION","type":"jira.issue.assign","value":{"assignType":"SPECIFY_USER"}}`
+	branch := json.RawMessage(`{"id":"3","component":"BRANCH","type":"jira.issue.related","schemaVersion":1,"value":{"relatedType":"subtasks"},"children":[` +
+		`{"id":"4","parentId":"3","component":"ACTION","type":"codebarrel.action.log","schemaVersion":1,"value":"{{issue.customfield_10709}}"},` + assign + `]}`)
+
+	parsed, err := ParseComponents([]json.RawMessage{branch}, ctx, map[string]string{"customfield_10709": "release_version"}, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(parsed) != 1 || parsed[0].Type.ValueString() != "branch" {
+		t.Fatalf("got %+v, want one branch component", parsed)
+	}
+	args, _ := typesMapToStringMap(ctx, parsed[0].Args)
+	if want := `{"component":"BRANCH","schemaVersion":1,"type":"jira.issue.related","value":{"relatedType":"subtasks"}}`; args["json"] != want {
+		t.Errorf("branch json:\n got %s\nwant %s", args["json"], want)
+	}
+	if len(parsed[0].Then) != 2 {
+		t.Fatalf("then: got %d actions, want 2", len(parsed[0].Then))
+	}
+	logArgs, _ := typesMapToStringMap(ctx, parsed[0].Then[0].Args)
+	if parsed[0].Then[0].Type.ValueString() != "log" || logArgs["message"] != "{{issue.release_version}}" {
+		t.Errorf("then[0]: got %s %v, want aliased log", parsed[0].Then[0].Type, logArgs)
+	}
+	if got := parsed[0].Then[1].Type.ValueString(); got != "raw" {
+		t.Errorf("then[1] type: got %q, want raw", got)
+	}
+
+	raws, err := BuildComponentsJSON(parsed, "", "", "", client.DefaultDebugLogPrefix, ctx, map[string]string{"release_version": "customfield_10709"})
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	got, _ := normalizeRawJSONArray(raws)
+	want, _ := normalizeRawJSONArray([]json.RawMessage{branch})
+	if withoutSchemaVersions(got) != withoutSchemaVersions(want) {
+		t.Errorf("round trip:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestBuildBranchJSON_Invalid(t *testing.T) {
+	for name, value := range map[string]string{
+		"not json":      `{"component":`,
+		"not a branch":  `{"component":"ACTION","type":"jira.issue.assign"}`,
+		"missing type":  `{"component":"BRANCH"}`,
+		"with children": `{"component":"BRANCH","type":"jira.issue.related","children":[{"component":"ACTION"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := BuildBranchJSON(map[string]string{"json": value}, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestBuildRaw_InvalidJSON(t *testing.T) {
+	for name, value := range map[string]string{
+		"not json":     `{"component":`,
+		"array":        `[]`,
+		"missing type": `{"component":"ACTION"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := buildRaw(map[string]string{"json": value}, "", "", ""); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestPreserveRawJSON(t *testing.T) {
+	ctx := context.Background()
+	rawArgs := func(s string) types.Map {
+		m, _ := stringMapToTypesMap(ctx, map[string]string{"json": s})
+		return m
+	}
+	raw := types.StringValue("raw")
+	configured := `{"type": "jira.issue.assign", "component": "ACTION", "schemaVersion": 4}`
+
+	prior := []componentModel{
+		{Type: raw, Args: rawArgs(configured)},
+		{Type: raw, Args: rawArgs(`{"component":"ACTION","type":"jira.issue.assign","value":"old"}`)},
+	}
+	parsed := []componentModel{
+		{Type: raw, Args: rawArgs(`{"component":"ACTION","schemaVersion":5,"type":"jira.issue.assign"}`)},
+		{Type: raw, Args: rawArgs(`{"component":"ACTION","type":"jira.issue.assign","value":"new"}`)},
+	}
+	preserveRawJSON(prior, parsed)
+
+	if got, _ := typesMapToStringMap(ctx, parsed[0].Args); got["json"] != configured {
+		t.Errorf("equivalent json: got %s, want the configured form", got["json"])
+	}
+	if got, _ := typesMapToStringMap(ctx, parsed[1].Args); !strings.Contains(got["json"], "new") {
+		t.Errorf("changed json: got %s, want the API value", got["json"])
+	}
+}
+
+func TestPreserveAliasForms(t *testing.T) {
+	ctx := context.Background()
+	aliases := map[string]string{"my_status": "status"}
+	reverse := map[string]string{"status": "my_status"}
+	args := func(m map[string]string) types.Map {
+		v, _ := stringMapToTypesMap(ctx, m)
+		return v
+	}
+	log := types.StringValue("log")
+
+	// The config writes the field ID in one component and the alias in the
+	// other; reading returns the alias in both.
+	prior := []componentModel{
+		{Type: log, Args: args(map[string]string{"message": "{{issue.status}}"}), When: types.MapNull(types.StringType),
+			Then: []innerActionModel{{Type: log, Args: args(map[string]string{"message": "{{issue.status.name}}"})}}},
+		{Type: log, Args: args(map[string]string{"message": "{{issue.my_status}}"}), When: args(map[string]string{"first": "{{issue.status.name}}", "operator": "equals", "second": "Done"})},
+		{Type: log, Args: args(map[string]string{"message": "{{issue.status}} old"})},
+	}
+	parsed := []componentModel{
+		{Type: log, Args: args(unresolveAliases(map[string]string{"message": "{{issue.status}}"}, reverse)), When: types.MapNull(types.StringType),
+			Then: []innerActionModel{{Type: log, Args: args(unresolveAliases(map[string]string{"message": "{{issue.status.name}}"}, reverse))}}},
+		{Type: log, Args: args(map[string]string{"message": "{{issue.my_status}}"}), When: args(map[string]string{"first": "{{issue.my_status.name}}", "operator": "equals", "second": "Done"})},
+		{Type: log, Args: args(map[string]string{"message": "{{issue.my_status}} new"})},
+	}
+	preserveAliasForms(prior, parsed, aliases)
+
+	for i, want := range []map[string]string{
+		{"message": "{{issue.status}}"},
+		{"message": "{{issue.my_status}}"},
+		{"message": "{{issue.my_status}} new"},
+	} {
+		if got, _ := typesMapToStringMap(ctx, parsed[i].Args); !maps.Equal(got, want) {
+			t.Errorf("component %d args: got %v, want %v", i, got, want)
+		}
+	}
+	if got, _ := typesMapToStringMap(ctx, parsed[0].Then[0].Args); got["message"] != "{{issue.status.name}}" {
+		t.Errorf("then action args: got %v, want the field ID form", got)
+	}
+	if got, _ := typesMapToStringMap(ctx, parsed[1].When); got["first"] != "{{issue.status.name}}" {
+		t.Errorf("when: got %v, want the field ID form", got)
+	}
+}
+
+func TestPreserveEmptyBranches(t *testing.T) {
+	prior := []componentModel{
+		{Then: []innerActionModel{{}}, Else: []innerActionModel{}},
+		{Then: nil, Else: nil},
+	}
+	parsed := []componentModel{
+		{Then: []innerActionModel{{}}, Else: nil},
+		{Then: nil, Else: nil},
+	}
+
+	preserveEmptyBranches(prior, parsed)
+
+	if parsed[0].Else == nil || len(parsed[0].Else) != 0 {
+		t.Errorf("component 0 else: got %#v, want empty non-nil list", parsed[0].Else)
+	}
+	if parsed[1].Then != nil || parsed[1].Else != nil {
+		t.Errorf("component 1: omitted branches should stay nil, got then=%#v else=%#v", parsed[1].Then, parsed[1].Else)
+	}
+}
+
+func TestPreserveDefaultArgs(t *testing.T) {
+	ctx := context.Background()
+	webhook := map[string]string{
+		"version_field":     "customfield_10709",
+		"category":          "Deploy",
+		"title":             "Build",
+		"url":               "https://ci.example.com/1",
+		"continue_on_error": "false",
+		"response_enabled":  "false",
+	}
+	webhookArgs, _ := stringMapToTypesMap(ctx, webhook)
+	condition := map[string]string{"first": "a", "operator": "equals", "second": "b", "match_type": "all"}
+	condArgs, _ := stringMapToTypesMap(ctx, condition)
+	null := types.MapNull(types.StringType)
+	config := []componentModel{
+		{Type: types.StringValue("add_release_related_work"), Args: webhookArgs, When: null},
+		{
+			Type: types.StringValue("condition"), Args: condArgs, When: null,
+			Then: []innerActionModel{{Type: types.StringValue("add_release_related_work"), Args: webhookArgs, When: null}},
+		},
+	}
+	raws, err := BuildComponentsJSON(config, "cloud-1", "user", "token", client.DefaultDebugLogPrefix, ctx, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	parsed, err := ParseComponents(raws, ctx, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if got, _ := typesMapToStringMap(ctx, parsed[0].Args); maps.Equal(got, webhook) {
+		t.Fatal("parser should leave default args out")
+	}
+
+	preserveDefaultArgs(ctx, config, parsed)
+	for name, args := range map[string]types.Map{"webhook": parsed[0].Args, "condition": parsed[1].Args, "then": parsed[1].Then[0].Args} {
+		want := webhook
+		if name == "condition" {
+			want = condition
+		}
+		if got, _ := typesMapToStringMap(ctx, args); !maps.Equal(got, want) {
+			t.Errorf("%s args: got %v, want %v", name, got, want)
+		}
+	}
+
+	// A prior value that differs from the API's isn't carried over.
+	changed := maps.Clone(webhook)
+	changed["continue_on_error"] = "true"
+	changedArgs, _ := stringMapToTypesMap(ctx, changed)
+	parsed, _ = ParseComponents(raws[:1], ctx, nil, client.DefaultDebugLogPrefix)
+	preserveDefaultArgs(ctx, []componentModel{{Type: types.StringValue("add_release_related_work"), Args: changedArgs}}, parsed)
+	if got, _ := typesMapToStringMap(ctx, parsed[0].Args); got["continue_on_error"] != "" {
+		t.Errorf("continue_on_error: got %q, want it left out", got["continue_on_error"])
+	}
+}
+
+func TestWhen_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	reverse := map[string]string{"customfield_10709": "release_version"}
+
+	args, _ := stringMapToTypesMap(ctx, map[string]string{"message": "hello"})
+	when, _ := stringMapToTypesMap(ctx, map[string]string{
+		"first":    "{{issue.release_version}}",
+		"operator": "not_equals",
+		"second":   "",
+	})
+	components := []componentModel{{Type: types.StringValue("log"), Args: args, When: when}}
+
+	raws, err := BuildComponentsJSON(components, "", "", "", client.DefaultDebugLogPrefix, ctx, aliases)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	if !strings.Contains(string(raws[0]), `"operator":"NOT_EQUALS"`) {
+		t.Errorf("expected uppercase operator in conditions, got %s", raws[0])
+	}
+	if !strings.Contains(string(raws[0]), "customfield_10709") {
+		t.Errorf("expected resolved alias in conditions, got %s", raws[0])
+	}
+
+	parsed, err := ParseComponents(raws, ctx, reverse, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	got, _ := typesMapToStringMap(ctx, parsed[0].When)
+	if got["first"] != "{{issue.release_version}}" || got["operator"] != "not_equals" {
+		t.Errorf("when: got %v", got)
+	}
+}
+
+func TestWhen_AbsentIsNull(t *testing.T) {
+	raw, _ := buildLog(map[string]string{"message": "hi"}, "", "", "")
+	parsed, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !parsed[0].When.IsNull() {
+		t.Errorf("when: got %v, want null", parsed[0].When)
+	}
+}
+
+func TestWhen_RejectedOnCondition(t *testing.T) {
+	ctx := context.Background()
+	when, _ := stringMapToTypesMap(ctx, map[string]string{"first": "a", "operator": "equals"})
+	components := []componentModel{{Type: types.StringValue("condition"), When: when}}
+	if _, err := BuildComponentsJSON(components, "", "", "", client.DefaultDebugLogPrefix, ctx, nil); err == nil {
+		t.Error("expected error for when on condition component")
+	}
+}
+
+func TestWhen_UnsupportedConditionType(t *testing.T) {
+	raw := json.RawMessage(`{"component":"ACTION","type":"jira.issue.comment","value":{"comment":"x"},"conditions":[{"type":"jira.user.condition","value":{}}]}`)
+	_, err := ParseComponents([]json.RawMessage{raw}, context.Background(), nil, client.DefaultDebugLogPrefix)
+	if err == nil || !strings.Contains(err.Error(), "components_json") {
+		t.Errorf("expected error pointing at components_json, got %v", err)
+	}
+}
+
+func TestPreserveComponentKeys(t *testing.T) {
+	ctx := context.Background()
+	argsA, _ := stringMapToTypesMap(ctx, map[string]string{"message": "a"})
+	argsB, _ := stringMapToTypesMap(ctx, map[string]string{"message": "b"})
+	argsC, _ := stringMapToTypesMap(ctx, map[string]string{"message": "c"})
+	comp := func(key string, args types.Map) componentModel {
+		k := types.StringNull()
+		if key != "" {
+			k = types.StringValue(key)
+		}
+		return componentModel{Key: k, Type: types.StringValue("log"), Args: args, When: types.MapNull(types.StringType)}
+	}
+
+	prior := []componentModel{comp("first", argsA), comp("second", argsB)}
+
+	// Reordered in the UI: keys follow content.
+	parsed := []componentModel{comp("", argsB), comp("", argsA)}
+	preserveComponentKeys(prior, parsed)
+	if parsed[0].Key.ValueString() != "second" || parsed[1].Key.ValueString() != "first" {
+		t.Errorf("reorder: got keys %q, %q", parsed[0].Key.ValueString(), parsed[1].Key.ValueString())
+	}
+
+	// Content changed: key falls back to position.
+	parsed = []componentModel{comp("", argsC), comp("", argsB)}
+	preserveComponentKeys(prior, parsed)
+	if parsed[0].Key.ValueString() != "first" || parsed[1].Key.ValueString() != "second" {
+		t.Errorf("edit: got keys %q, %q", parsed[0].Key.ValueString(), parsed[1].Key.ValueString())
+	}
+}
+
+func TestComponentIDSources(t *testing.T) {
+	comp := func(key string) componentModel {
+		k := types.StringNull()
+		if key != "" {
+			k = types.StringValue(key)
+		}
+		return componentModel{Key: k, Type: types.StringValue("log")}
+	}
+	prior := []componentModel{comp("first"), comp(""), comp("second")}
+
+	tests := []struct {
+		name    string
+		prior   []componentModel
+		planned []componentModel
+		want    []int
+	}{
+		{name: "inserted at the top", prior: prior, planned: []componentModel{comp("new"), comp("first"), comp(""), comp("second")}, want: []int{-1, 0, -1, 2}},
+		{name: "reordered", prior: prior, planned: []componentModel{comp("second"), comp(""), comp("first")}, want: []int{2, 1, 0}},
+		{name: "new key falls back to an unkeyed position", prior: prior, planned: []componentModel{comp("first"), comp("renamed")}, want: []int{0, 1}},
+		{name: "no planned keys", prior: prior, planned: []componentModel{comp(""), comp("")}, want: nil},
+		{name: "no prior keys", prior: []componentModel{comp("")}, planned: []componentModel{comp("first")}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := componentIDSources(tt.prior, tt.planned); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleComponentIDSources_DebugExpansion(t *testing.T) {
+	ctx := context.Background()
+	r := &ruleResource{client: &client.Client{WebhookUser: "u", WebhookToken: "t", DebugLogPrefix: client.DefaultDebugLogPrefix}}
+	logArgs, _ := stringMapToTypesMap(ctx, map[string]string{"message": "a"})
+	releaseArgs, _ := stringMapToTypesMap(ctx, map[string]string{
+		"version_field": "fixVersions", "category": "Docs", "title": "T", "url": "https://example.com", "debug": "true",
+	})
+	a := componentModel{Key: types.StringValue("a"), Type: types.StringValue("log"), Args: logArgs, When: types.MapNull(types.StringType)}
+	rel := componentModel{Key: types.StringValue("r"), Type: types.StringValue("add_release_related_work"), Args: releaseArgs, When: types.MapNull(types.StringType)}
+
+	// The API holds [a, debug logs..., webhook]; the config moves r first.
+	got := r.componentIDSources(ctx, []componentModel{a, rel}, []componentModel{rel, a})
+	want := []int{1, 2, 3, 4, 5, 0}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// TestGolden_WebhookAuthHeaderStable reads the captured debug_webhook rule,
+// parses its components and rebuilds them with the credentials encoded in the
+// captured header, asserting the secure Authorization header survives exactly.
+func TestGolden_WebhookAuthHeaderStable(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(repoRoot(), "testdata", "golden", "debug_webhook.json"))
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	var rule struct {
+		Components []json.RawMessage `json:"components"`
+	}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		t.Fatalf("parsing golden file: %v", err)
+	}
+
+	type header struct {
+		HeaderSecure bool   `json:"headerSecure"`
+		Name         string `json:"name"`
+		Value        string `json:"value"`
+	}
+	findWebhook := func(raws []json.RawMessage) (url string, h header) {
+		for _, raw := range raws {
+			var action struct {
+				Type  string `json:"type"`
+				Value struct {
+					URL     string   `json:"url"`
+					Headers []header `json:"headers"`
+				} `json:"value"`
+			}
+			if err := json.Unmarshal(raw, &action); err == nil && action.Type == "jira.issue.outgoing.webhook" {
+				return action.Value.URL, action.Value.Headers[0]
+			}
+		}
+		t.Fatal("no webhook action found")
+		return "", header{}
+	}
+
+	url, golden := findWebhook(rule.Components)
+	creds, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(golden.Value, "Basic "))
+	if err != nil {
+		t.Fatalf("decoding golden header: %v", err)
+	}
+	user, token, _ := strings.Cut(string(creds), ":")
+	cloudID := strings.Split(strings.TrimPrefix(url, "https://api.atlassian.com/ex/jira/"), "/")[0]
+
+	if n := foreignWebhookAuth(rule.Components, relatedWorkAuthHeader(user, token)); n != 0 {
+		t.Errorf("foreignWebhookAuth with matching creds: got %d, want 0", n)
+	}
+	if n := foreignWebhookAuth(rule.Components, relatedWorkAuthHeader("someone-else", token)); n != 1 {
+		t.Errorf("foreignWebhookAuth with other creds: got %d, want 1", n)
+	}
+	redacted := make([]json.RawMessage, len(rule.Components))
+	for i, c := range rule.Components {
+		redacted[i] = json.RawMessage(strings.ReplaceAll(string(c), golden.Value, redactedHeaderValue))
+	}
+	if n := foreignWebhookAuth(redacted, relatedWorkAuthHeader("someone-else", token)); n != 0 {
+		t.Errorf("foreignWebhookAuth with redacted header: got %d, want 0", n)
+	}
+
+	ctx := context.Background()
+	parsed, err := ParseComponents(rule.Components, ctx, nil, client.DefaultDebugLogPrefix)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	rebuilt, err := BuildComponentsJSON(parsed, cloudID, user, token, client.DefaultDebugLogPrefix, ctx, nil)
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	_, got := findWebhook(rebuilt)
+	if got != golden {
+		t.Errorf("header changed across read/write:\n got  %+v\n want %+v", got, golden)
+	}
+}
+
+func TestSupportedComponentTypes(t *testing.T) {
+	got := SupportedComponentTypes()
+	if !slices.IsSorted(got) {
+		t.Errorf("not sorted: %v", got)
+	}
+	for _, special := range []string{"condition", "user_condition", "branch"} {
+		if !slices.Contains(got, special) {
+			t.Errorf("missing special-cased %s: %v", special, got)
+		}
+	}
+	for userType := range componentRegistry {
+		if !slices.Contains(got, userType) {
+			t.Errorf("missing %q: %v", userType, got)
+		}
+	}
+}
+
+func TestStructuredFallbackReasons(t *testing.T) {
+	ctx := context.Background()
+	trigger, err := BuildTriggerJSON("scheduled", map[string]string{"cron": "0 0 9 * * ?"}, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("build trigger: %v", err)
+	}
+	logRaw, err := buildLog(map[string]string{"message": "hi"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build log: %v", err)
+	}
+
+	if got := StructuredFallbackReasons(trigger, []json.RawMessage{logRaw}, ctx, client.DefaultDebugLogPrefix); len(got) != 0 {
+		t.Errorf("supported rule: got %v", got)
+	}
+
+	// Unknown component types parse as raw; only components that can't be
+	// parsed at all, like an if/else-if container, are reported.
+	block := `{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[],"conditions":[` +
+		`{"component":"CONDITION","type":"jira.comparator.condition","value":{"first":"a","operator":"EQUALS","second":"b"}}]}`
+	elseIf := json.RawMessage(`{"component":"CONDITION","type":"jira.condition.container.block","children":[` + block + `,` + block + `]}`)
+	got := StructuredFallbackReasons(
+		json.RawMessage(`{"component":"TRIGGER","type":"jira.manual.trigger"}`),
+		[]json.RawMessage{
+			json.RawMessage(`{"component":"ACTION","type":"com.atlassian.x"}`),
+			logRaw,
+			elseIf,
+		}, ctx, client.DefaultDebugLogPrefix)
+	want := []string{
+		`trigger: unrecognized API trigger type: "jira.manual.trigger"`,
+		`component 2: condition has an else-if branch; only if/else is supported, use components_json escape hatch`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("reasons:\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &componentsJSONDataSource{}
+
+// componentsJSONDataSource converts structured components into the
+// components_json the rule resource would send, for switching a rule between
+// the two representations without a diff.
+type componentsJSONDataSource struct {
+	client *client.Client
+}
+
+type componentsJSONDataSourceModel struct {
+	Components     []componentModel `tfsdk:"components"`
+	ComponentsJSON types.String     `tfsdk:"components_json"`
+}
+
+func NewComponentsJSONDataSource() datasource.DataSource {
+	return &componentsJSONDataSource{}
+}
+
+func (d *componentsJSONDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_components_json"
+}
+
+func (d *componentsJSONDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	innerAction := schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Action type.",
+			},
+			"args": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Action arguments as key-value pairs.",
+			},
+			"when": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Optional condition attached to this action's own conditions: a comparator (first, operator, second) or a jql query.",
+			},
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Converts structured components into the equivalent normalized components_json, so a rule can move between the two representations without a diff.",
+		Attributes: map[string]schema.Attribute{
+			"components": schema.ListNestedAttribute{
+				Required:    true,
+				Description: "Structured components, exactly as in the jira-automation_rule components attribute.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Optional:    true,
+							Description: "Optional stable identifier for the component. Not part of the JSON.",
+						},
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Component type (e.g. condition, user_condition, branch, log, comment, assign_issue, set_property, add_release_related_work, raw).",
+						},
+						"args": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Component arguments as key-value pairs.",
+						},
+						"when": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Optional condition attached to this action's own conditions: a comparator (first, operator, second) or a jql query.",
+						},
+						"then": schema.ListNestedAttribute{
+							Optional:     true,
+							Description:  "Actions to execute when the condition is true, or for each issue a branch selects.",
+							NestedObject: innerAction,
+						},
+						"else": schema.ListNestedAttribute{
+							Optional:     true,
+							Description:  "Actions to execute when the condition is false.",
+							NestedObject: innerAction,
+						},
+					},
+				},
+			},
+			"components_json": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The components as a normalized JSON array, as the rule resource would send them. Sensitive because add_release_related_work embeds the webhook credentials.",
+			},
+		},
+	}
+}
+
+func (d *componentsJSONDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData))
+		return
+	}
+	d.client = c
+}
+
+func (d *componentsJSONDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model componentsJSONDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	raws, err := BuildComponentsJSON(model.Components, d.client.CloudID, d.client.WebhookUser, d.client.WebhookToken, d.client.DebugLogPrefix, ctx, d.client.FieldAliases)
+	if err != nil {
+		resp.Diagnostics.AddError("Error building components JSON", err.Error())
+		return
+	}
+	norm, err := normalizeRawJSONArray(raws)
+	if err != nil {
+		resp.Diagnostics.AddError("Error normalizing components", err.Error())
+		return
+	}
+
+	model.ComponentsJSON = types.StringValue(norm)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestComponentsJSONDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	d := &componentsJSONDataSource{client: &client.Client{
+		CloudID:        "cloud-123",
+		DebugLogPrefix: client.DefaultDebugLogPrefix,
+		FieldAliases:   map[string]string{"release_version": "customfield_10709"},
+	}}
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+
+	model := componentsJSONDataSourceModel{
+		Components: []componentModel{{
+			Key:  types.StringValue("log"),
+			Type: types.StringValue("log"),
+			Args: types.MapValueMust(types.StringType, map[string]attr.Value{"message": types.StringValue("{{issue.release_version}}")}),
+			When: types.MapNull(types.StringType),
+		}},
+		ComponentsJSON: types.StringNull(),
+	}
+	// Config has no setter, so build its raw value through a State.
+	raw := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := raw.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("building config: %v", diags)
+	}
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw.Raw}}
+	resp := &datasource.ReadResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+
+	d.Read(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	var got componentsJSONDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &got)...)
+	out := got.ComponentsJSON.ValueString()
+	if !strings.HasPrefix(out, `[{"component":"ACTION"`) || !strings.Contains(out, `"value":"{{issue.customfield_10709}}"`) {
+		t.Errorf("components_json: got %s", out)
+	}
+	// Normalized output is what the rule resource keeps equivalent on read.
+	if norm, err := normalizeComponentsJSON([]byte(out)); err != nil || norm != out {
+		t.Errorf("output is not normalized: %v\n got %s\nwant %s", err, out, norm)
+	}
+}
@@ -2,11 +2,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"os"
 
 	"terraform-provider-jira-automation/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -14,18 +18,28 @@ import (
 )
 
 var _ provider.Provider = &jiraAutomationProvider{}
+var _ provider.ProviderWithFunctions = &jiraAutomationProvider{}
 
 type jiraAutomationProvider struct {
 	version string
 }
 
 type jiraAutomationProviderModel struct {
-	SiteURL      types.String `tfsdk:"site_url"`
-	Email        types.String `tfsdk:"email"`
-	APIToken     types.String `tfsdk:"api_token"`
-	WebhookUser  types.String `tfsdk:"webhook_user"`
-	WebhookToken types.String `tfsdk:"webhook_token"`
-	FieldAliases types.Map    `tfsdk:"field_aliases"`
+	SiteURL        types.String `tfsdk:"site_url"`
+	APIBaseURL     types.String `tfsdk:"api_base_url"`
+	Email          types.String `tfsdk:"email"`
+	APIToken       types.String `tfsdk:"api_token"`
+	WebhookUser    types.String `tfsdk:"webhook_user"`
+	WebhookToken   types.String `tfsdk:"webhook_token"`
+	FieldAliases   types.Map    `tfsdk:"field_aliases"`
+	ManageLabel    types.Bool   `tfsdk:"manage_label"`
+	LabelName      types.String `tfsdk:"managed_label_name"`
+	JSONIndent     types.Bool   `tfsdk:"json_indent"`
+	DebugPrefix    types.String `tfsdk:"debug_log_prefix"`
+	AliasJSON      types.Bool   `tfsdk:"resolve_aliases_in_json"`
+	DefaultEnabled types.Bool   `tfsdk:"default_enabled"`
+	ReadOnly       types.Bool   `tfsdk:"read_only"`
+	SystemAuthors  types.List   `tfsdk:"system_rule_authors"`
 }
 
 func New(version string) func() provider.Provider {
@@ -47,6 +61,12 @@ func (p *jiraAutomationProvider) Schema(_ context.Context, _ provider.SchemaRequ
 				Description: "The Jira site URL (e.g. https://yoursite.atlassian.net). Can also be set via JIRA_SITE_URL or ATLASSIAN_SITE_URL env var.",
 				Optional:    true,
 			},
+			"api_base_url": schema.StringAttribute{
+				Description: "Base URL of the Atlassian API gateway serving the automation API. Defaults to https://api.atlassian.com. " +
+					"Set it for regional or FedRAMP clouds that use a different host; the site's cloud ID is still appended to the path. " +
+					"Must be an https URL without a path. Can also be set via JIRA_API_BASE_URL env var.",
+				Optional: true,
+			},
 			"email": schema.StringAttribute{
 				Description: "The email for Jira API authentication. Can also be set via JIRA_EMAIL or ATLASSIAN_USER env var.",
 				Optional:    true,
@@ -67,10 +87,53 @@ func (p *jiraAutomationProvider) Schema(_ context.Context, _ provider.SchemaRequ
 			},
 			"field_aliases": schema.MapAttribute{
 				Description: "Map of friendly alias names to Jira custom field IDs (e.g. release_version = \"customfield_10709\"). " +
-					"Aliases can be used in smart values ({{issue.ALIAS}}) and as bare arg values; the provider resolves them to field IDs on write and reverses on read.",
+					"Aliases can be used in smart values ({{issue.ALIAS}}) and as bare arg values; the provider resolves them to field IDs on write and reverses on read. " +
+					"Args that use the field ID directly are kept as written.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"manage_label": schema.BoolAttribute{
+				Description: "Whether to tag managed rules with the managed label after create and update. Defaults to true. " +
+					"Set to false to skip the label lookup entirely.",
+				Optional: true,
+			},
+			"managed_label_name": schema.StringAttribute{
+				Description: "Name of the label used to tag managed rules. Defaults to managed-by:terraform. The label must already exist in the project.",
+				Optional:    true,
+			},
+			"debug_log_prefix": schema.StringAttribute{
+				Description: "Prefix for the log actions generated by debug = \"true\". Defaults to \"[DEBUG add_release_related_work] \". " +
+					"Changing it makes existing debug logs read back as regular log components until the rule is re-applied.",
+				Optional: true,
+			},
+			"json_indent": schema.BoolAttribute{
+				Description: "Store trigger_json and components_json read from the API as indented JSON for readability. Defaults to false. " +
+					"Values still compare semantically, so toggling this never causes a diff.",
+				Optional: true,
+			},
+			"default_enabled": schema.BoolAttribute{
+				Description: "Value of enabled for jira-automation_rule resources that don't set it. Defaults to true. " +
+					"Set to false, e.g. in a staging environment, to create rules disabled unless they opt in. " +
+					"Like any default, changing it also plans an update for existing rules that don't set enabled.",
+				Optional: true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Refuse every write: creating, updating, deleting or changing the state of rules fails with an error, while reads, imports and data sources still work. " +
+					"Defaults to false. Payloads are still built and validated, so terraform plan against production credentials can't mutate anything.",
+				Optional: true,
+			},
+			"system_rule_authors": schema.ListAttribute{
+				Description: "Account IDs that author Atlassian-managed rules on the site, such as the Automation for Jira app's. " +
+					"Rules they authored are reported as system_owned, warned about on read, and only updated with allow_system_rule = true. " +
+					"The API doesn't mark such rules, so none are detected unless this is set.",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"resolve_aliases_in_json": schema.BoolAttribute{
+				Description: "Also resolve field_aliases in trigger_json and components_json. Defaults to false. " +
+					"When enabled, every string value in the raw JSON is treated like a structured arg: aliases in smart values and strings that exactly match an alias are replaced, and reversed on read.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -87,11 +150,18 @@ func (p *jiraAutomationProvider) Configure(ctx context.Context, req provider.Con
 	apiToken := stringValueOrEnv(config.APIToken, "JIRA_API_TOKEN", "ATLASSIAN_TOKEN")
 	webhookUser := stringValueOrEnv(config.WebhookUser, "JIRA_WEBHOOK_USER")
 	webhookToken := stringValueOrEnv(config.WebhookToken, "JIRA_WEBHOOK_TOKEN")
+	apiBaseURL := stringValueOrEnv(config.APIBaseURL, "JIRA_API_BASE_URL")
 
 	if siteURL == "" {
 		resp.Diagnostics.AddError("Missing site_url", "site_url must be set in provider config or JIRA_SITE_URL / ATLASSIAN_SITE_URL env var.")
 		return
 	}
+	if apiBaseURL != "" {
+		if err := validateAPIBaseURL(apiBaseURL); err != nil {
+			resp.Diagnostics.AddError("Invalid api_base_url", err.Error())
+			return
+		}
+	}
 	if email == "" {
 		resp.Diagnostics.AddError("Missing email", "email must be set in provider config or JIRA_EMAIL / ATLASSIAN_USER env var.")
 		return
@@ -117,6 +187,45 @@ func (p *jiraAutomationProvider) Configure(ctx context.Context, req provider.Con
 		return
 	}
 
+	if apiBaseURL != "" {
+		c.BaseURL = client.AutomationBaseURL(apiBaseURL, c.CloudID)
+	}
+	if !config.ManageLabel.IsNull() && !config.ManageLabel.IsUnknown() {
+		c.ManageLabel = config.ManageLabel.ValueBool()
+	}
+	if !config.LabelName.IsNull() && !config.LabelName.IsUnknown() {
+		c.ManagedLabelName = config.LabelName.ValueString()
+	}
+	if !config.JSONIndent.IsNull() && !config.JSONIndent.IsUnknown() {
+		c.JSONIndent = config.JSONIndent.ValueBool()
+	}
+	if !config.AliasJSON.IsNull() && !config.AliasJSON.IsUnknown() {
+		c.AliasRawJSON = config.AliasJSON.ValueBool()
+	}
+	if !config.DefaultEnabled.IsNull() && !config.DefaultEnabled.IsUnknown() {
+		c.DefaultEnabled = config.DefaultEnabled.ValueBool()
+	}
+	if !config.ReadOnly.IsNull() && !config.ReadOnly.IsUnknown() {
+		c.ReadOnly = config.ReadOnly.ValueBool()
+	}
+	if !config.SystemAuthors.IsNull() && !config.SystemAuthors.IsUnknown() {
+		resp.Diagnostics.Append(config.SystemAuthors.ElementsAs(ctx, &c.SystemAuthors, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if !config.DebugPrefix.IsNull() && !config.DebugPrefix.IsUnknown() {
+		if config.DebugPrefix.ValueString() == "" {
+			resp.Diagnostics.AddError("Invalid debug_log_prefix", "debug_log_prefix must not be empty.")
+			return
+		}
+		c.DebugLogPrefix = config.DebugPrefix.ValueString()
+	}
+	if c.ManageLabel && c.ManagedLabelName == "" {
+		resp.Diagnostics.AddError("Invalid managed_label_name", "managed_label_name must not be empty while manage_label is enabled.")
+		return
+	}
+
 	resp.DataSourceData = c
 	resp.ResourceData = c
 }
@@ -124,13 +233,51 @@ func (p *jiraAutomationProvider) Configure(ctx context.Context, req provider.Con
 func (p *jiraAutomationProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewRuleResource,
+		NewRuleStateResource,
 	}
 }
 
 func (p *jiraAutomationProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewRulesDataSource,
+		NewWhoamiDataSource,
+		NewComponentsJSONDataSource,
+		NewRuleTemplateDataSource,
+	}
+}
+
+func (p *jiraAutomationProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewStatusTransitionFunction,
+	}
+}
+
+// validateAPIBaseURL checks that u is an absolute https URL naming only a host,
+// since the automation API path is appended to it.
+func validateAPIBaseURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("api_base_url %q is not a valid URL: %w", u, err)
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("api_base_url %q must be an https URL such as %s", u, client.DefaultAPIBaseURL)
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+		return fmt.Errorf("api_base_url %q must not contain a path, query, fragment, or credentials", u)
+	}
+	return nil
+}
+
+// checkWritable adds an error to diags and returns false if the provider is
+// in read-only mode. op names the refused operation, e.g. "create rule r1".
+func checkWritable(c *client.Client, op string, diags *diag.Diagnostics) bool {
+	if !c.ReadOnly {
+		return true
 	}
+	diags.AddError("Provider is read-only",
+		fmt.Sprintf("Refusing to %s: the provider is configured with read_only = true, which allows plans and reads but no changes to Jira. "+
+			"Apply with a provider configuration that doesn't set read_only.", op))
+	return false
 }
 
 // stringValueOrEnv returns the Terraform config value if set, otherwise checks env vars.
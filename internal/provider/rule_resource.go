@@ -1,30 +1,45 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"terraform-provider-jira-automation/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 var (
-	_ resource.Resource                = &ruleResource{}
-	_ resource.ResourceWithImportState = &ruleResource{}
+	_ resource.Resource                   = &ruleResource{}
+	_ resource.ResourceWithImportState    = &ruleResource{}
+	_ resource.ResourceWithModifyPlan     = &ruleResource{}
+	_ resource.ResourceWithValidateConfig = &ruleResource{}
 )
 
 type ruleResource struct {
@@ -34,15 +49,30 @@ type ruleResource struct {
 type ruleResourceModel struct {
 	ID             types.String         `tfsdk:"id"`
 	Name           types.String         `tfsdk:"name"`
+	Description    types.String         `tfsdk:"description"`
 	Enabled        types.Bool           `tfsdk:"enabled"`
 	State          types.String         `tfsdk:"state"`
 	Scope          types.List           `tfsdk:"scope"`
 	Labels         types.List           `tfsdk:"labels"`
+	Metadata       types.Map            `tfsdk:"metadata"`
+	AuthorID       types.String         `tfsdk:"author_account_id"`
+	ActorType      types.String         `tfsdk:"actor_type"`
+	ActorAccountID types.String         `tfsdk:"actor_account_id"`
+	WebhookURL     types.String         `tfsdk:"webhook_url"`
+	SystemOwned    types.Bool           `tfsdk:"system_owned"`
+	AllowSystem    types.Bool           `tfsdk:"allow_system_rule"`
+	Recreate       types.Bool           `tfsdk:"recreate_components_on_update"`
 	ProjectID      types.String         `tfsdk:"project_id"`
+	ScopeARIs      types.List           `tfsdk:"scope_aris"`
 	Trigger        *triggerModel        `tfsdk:"trigger"`
 	TriggerJSON    jsontypes.Normalized `tfsdk:"trigger_json"`
 	Components     []componentModel     `tfsdk:"components"`
 	ComponentsJSON jsontypes.Normalized `tfsdk:"components_json"`
+
+	GeneratedTriggerJSON    types.String `tfsdk:"generated_trigger_json"`
+	GeneratedComponentsJSON types.String `tfsdk:"generated_components_json"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewRuleResource() resource.Resource {
@@ -53,7 +83,7 @@ func (r *ruleResource) Metadata(_ context.Context, req resource.MetadataRequest,
 	resp.TypeName = req.ProviderTypeName + "_rule"
 }
 
-func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *ruleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a Jira Automation rule. Note: the public API has no DELETE endpoint, so terraform destroy will disable the rule instead of deleting it.",
 		Attributes: map[string]schema.Attribute{
@@ -68,11 +98,16 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Required:    true,
 				Description: "Rule name.",
 			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Rule description, shown in the Jira Automation UI. Defaults to empty.",
+			},
 			"enabled": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Default:     booldefault.StaticBool(true),
-				Description: "Whether the rule is enabled.",
+				Description: "Whether the rule is enabled. Defaults to the provider's default_enabled, which is true unless set.",
 			},
 			"state": schema.StringAttribute{
 				Computed:    true,
@@ -92,14 +127,107 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"labels": schema.ListAttribute{
 				Computed:    true,
 				ElementType: types.StringType,
-				Description: "Rule labels (read-only). The provider auto-tags rules with managed-by:terraform but labels cannot be set via config. Use the Jira UI to manage labels.",
+				Description: "Rule labels (read-only). The provider auto-tags rules with its managed label (managed-by:terraform by default) and with the labels for metadata, but labels cannot be set via config. Use the Jira UI to manage other labels.",
 				PlanModifiers: []planmodifier.List{
 					listplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Key/value metadata for the rule, such as its owning team. The API has no field for it, so each entry is stored as a key:value rule label " +
+					"(team = \"payments\" becomes the label team:payments), created in the project if missing. Labels for keys removed from metadata are removed from the rule. " +
+					"Only project-scoped rules have labels.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(metadataKeyPattern, "must be non-empty and must not contain ':'")),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"author_account_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Account ID of the rule's author (read-only). Set to the provider's user on create; imported rules keep their original author.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"actor_type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Who the rule's actions run as, which decides their permissions and e.g. who comments are posted by: " +
+					"ACCOUNT_ID (the account in actor_account_id) or EVENT_INITIATOR (the user who triggered the rule). " +
+					"New rules default to ACCOUNT_ID; if unset, an existing rule keeps its actor.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(client.ActorAccountID, client.ActorEventInitiator),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"actor_account_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Account the rule runs as when actor_type is ACCOUNT_ID. Defaults to the provider's user; null for other actor types.",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"system_owned": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the rule is system-owned: authored by one of the provider's system_rule_authors.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"allow_system_rule": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow changes to a system-owned rule. Without it, plans that would update such a rule fail.",
+			},
+			"recreate_components_on_update": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				Description: "Have the API recreate every component on update, which can repair a corrupted rule. " +
+					"Set to false to keep the IDs of components that match the current rule by position and type, for less churn in the rule's history.",
+			},
+			"webhook_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "Callback URL of an incoming-webhook trigger, built from its webhookToken; null for other triggers.",
+				PlanModifiers: []planmodifier.String{
+					webhookURLModifier{},
+				},
+			},
 			"project_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "Jira project numeric ID. Used to scope event-based triggers to a project.",
+				Description: "Jira project numeric ID. Used to scope event-based triggers to a project. Changing a rule's scope in place needs a Jira global admin, so changing it replaces the rule.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(numericIDPattern,
+						"must be the numeric project ID (e.g. 10001), not the project key; find it under Project settings > Details or via GET /rest/api/3/project/{key}"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(projectIDRequiresReplace,
+						"Replaces the rule when project_id no longer matches the project in its scope.",
+						"Replaces the rule when `project_id` no longer matches the project in its scope."),
+				},
+			},
+			"scope_aris": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Scope ARIs to create the rule with, used verbatim, for scopes project_id can't express. " +
+					"Mutually exclusive with project_id. Changing a rule's scope in place needs a Jira global admin, so changing it replaces the rule.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("project_id")),
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(scopeARIPattern,
+						"must be a scope ARI such as ari:cloud:jira:<cloudId>:project/10001")),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplaceIf(scopeARIsRequiresReplace,
+						"Replaces the rule when scope_aris no longer matches its scope.",
+						"Replaces the rule when `scope_aris` no longer matches its scope."),
+				},
 			},
 			"trigger": schema.SingleNestedAttribute{
 				Optional:    true,
@@ -110,12 +238,19 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Attributes: map[string]schema.Attribute{
 					"type": schema.StringAttribute{
 						Required:    true,
-						Description: "Trigger type (e.g. status_transition).",
+						Description: "Trigger type (e.g. status_transition, scheduled, manual, incoming_webhook).",
 					},
 					"args": schema.MapAttribute{
 						Optional:    true,
 						ElementType: types.StringType,
 						Description: "Trigger arguments as key-value pairs.",
+						Validators:  []validator.Map{smartValueBracesValidator{}},
+					},
+					"when": schema.MapAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Optional condition on the trigger itself: a comparator (first, operator, second) or a jql query. The rule only runs when it holds.",
+						Validators:  []validator.Map{smartValueBracesValidator{}},
 					},
 				},
 			},
@@ -123,27 +258,44 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Optional:    true,
 				CustomType:  jsontypes.NormalizedType{},
 				Description: "Trigger configuration as a JSON string. Mutually exclusive with trigger.",
+				Validators: []validator.String{
+					triggerJSONValidator{},
+				},
 			},
 			"components": schema.ListNestedAttribute{
 				Optional:    true,
 				Description: "Structured component configuration. Mutually exclusive with components_json.",
 				Validators: []validator.List{
 					listvalidator.ExactlyOneOf(path.MatchRoot("components_json")),
+					nonEmptyComponentsValidator{},
+					uniqueComponentKeysValidator{},
+					commentVisibilityValidator{},
 				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Optional:    true,
+							Description: "Optional stable identifier for the component, unique within the rule. Kept in state only; the API has no field for it.",
+						},
 						"type": schema.StringAttribute{
 							Required:    true,
-							Description: "Component type (e.g. condition, log, comment, add_release_related_work).",
+							Description: "Component type (e.g. condition, user_condition, branch, log, comment, assign_issue, set_property, add_release_related_work, raw).",
 						},
 						"args": schema.MapAttribute{
 							Optional:    true,
 							ElementType: types.StringType,
 							Description: "Component arguments as key-value pairs.",
+							Validators:  []validator.Map{smartValueBracesValidator{}},
+						},
+						"when": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Optional condition attached to this action's own conditions: a comparator (first, operator, second) or a jql query. The action only runs when it holds.",
+							Validators:  []validator.Map{smartValueBracesValidator{}},
 						},
 						"then": schema.ListNestedAttribute{
 							Optional:    true,
-							Description: "Actions to execute when the condition is true.",
+							Description: "Actions to execute when the condition is true, or for each issue a branch selects.",
 							NestedObject: schema.NestedAttributeObject{
 								Attributes: map[string]schema.Attribute{
 									"type": schema.StringAttribute{
@@ -154,6 +306,13 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 										Optional:    true,
 										ElementType: types.StringType,
 										Description: "Action arguments as key-value pairs.",
+										Validators:  []validator.Map{smartValueBracesValidator{}},
+									},
+									"when": schema.MapAttribute{
+										Optional:    true,
+										ElementType: types.StringType,
+										Description: "Optional condition attached to this action's own conditions: a comparator (first, operator, second) or a jql query. The action only runs when it holds.",
+										Validators:  []validator.Map{smartValueBracesValidator{}},
 									},
 								},
 							},
@@ -171,6 +330,13 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 										Optional:    true,
 										ElementType: types.StringType,
 										Description: "Action arguments as key-value pairs.",
+										Validators:  []validator.Map{smartValueBracesValidator{}},
+									},
+									"when": schema.MapAttribute{
+										Optional:    true,
+										ElementType: types.StringType,
+										Description: "Optional condition attached to this action's own conditions: a comparator (first, operator, second) or a jql query. The action only runs when it holds.",
+										Validators:  []validator.Map{smartValueBracesValidator{}},
 									},
 								},
 							},
@@ -182,9 +348,71 @@ func (r *ruleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Optional:    true,
 				CustomType:  jsontypes.NormalizedType{},
 				Description: "Components (actions/conditions) as a JSON array string. Mutually exclusive with components.",
+				Validators: []validator.String{
+					componentsJSONValidator{},
+				},
+			},
+			"generated_trigger_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The trigger JSON the provider sends to the API, computed at plan time. Useful for debugging API rejections.",
+			},
+			"generated_components_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The components JSON the provider sends to the API, computed at plan time. Secure header values (webhook credentials) are redacted.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.BlockAll(ctx),
+		},
+	}
+}
+
+// defaultOperationTimeout bounds a CRUD operation, retries included, when the
+// timeouts block doesn't set one.
+const defaultOperationTimeout = 20 * time.Minute
+
+// nullTimeouts is the timeouts value of a rule without a timeouts block, such
+// as one being imported.
+func nullTimeouts(ctx context.Context) timeouts.Value {
+	t := timeouts.BlockAll(ctx).Type().(timeouts.Type)
+	return timeouts.Value{Object: types.ObjectNull(t.AttrTypes)}
+}
+
+// withTimeout returns a copy of r whose client requests are bound to ctx
+// with the given deadline. The caller must call cancel.
+func (r *ruleResource) withTimeout(ctx context.Context, timeout time.Duration) (*ruleResource, context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return &ruleResource{client: r.client.WithContext(ctx)}, ctx, cancel
+}
+
+// ValidateConfig warns when the trigger and components use different forms,
+// e.g. a structured trigger with components_json. That works, but each half
+// is read back in its own form, so the rule is neither fully structured nor
+// plain API JSON.
+func (r *ruleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var trigger types.Object
+	var components types.List
+	var triggerJSON, componentsJSON jsontypes.Normalized
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("trigger"), &trigger)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("trigger_json"), &triggerJSON)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("components"), &components)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("components_json"), &componentsJSON)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var structured, raw string
+	switch {
+	case !trigger.IsNull() && !componentsJSON.IsNull():
+		structured, raw = "trigger", "components_json"
+	case !triggerJSON.IsNull() && !components.IsNull():
+		structured, raw = "components", "trigger_json"
+	default:
+		return
 	}
+	resp.Diagnostics.AddWarning("Rule mixes structured and JSON forms",
+		fmt.Sprintf("This rule sets %s together with %s. Each is read back in its own form, so diffs, imports and import-gen output treat the two halves differently. "+
+			"Prefer trigger with components, or trigger_json with components_json.", structured, raw))
 }
 
 func (r *ruleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -200,12 +428,104 @@ func (r *ruleResource) Configure(_ context.Context, req resource.ConfigureReques
 	r.client = c
 }
 
+// ModifyPlan fills the generated_* attributes so the final API payload shows in
+// terraform plan. If it can't be built yet (unknown values, invalid args) the
+// attributes stay unknown and any error surfaces at apply as before.
+func (r *ruleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan ruleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// enabled has no static default: it follows the provider's default_enabled.
+	var configEnabled types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("enabled"), &configEnabled)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if configEnabled.IsNull() {
+		plan.Enabled = types.BoolValue(r.client.DefaultEnabled)
+		// state was planned from the prior enabled; keep it in step.
+		if r.client.DefaultEnabled {
+			plan.State = types.StringValue("ENABLED")
+		} else {
+			plan.State = types.StringValue("DISABLED")
+		}
+	}
+
+	// An account implies ACCOUNT_ID, and other actor types have no account.
+	var configActorType, configActorAccount types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("actor_type"), &configActorType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("actor_account_id"), &configActorAccount)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !configActorAccount.IsNull() {
+		if configActorType.IsNull() {
+			plan.ActorType = types.StringValue(client.ActorAccountID)
+		} else if !configActorType.IsUnknown() && configActorType.ValueString() != client.ActorAccountID {
+			resp.Diagnostics.AddAttributeError(path.Root("actor_account_id"), "Invalid actor",
+				fmt.Sprintf("actor_account_id can only be set when actor_type is %s, not %s.", client.ActorAccountID, configActorType.ValueString()))
+			return
+		}
+	}
+	if !plan.ActorType.IsNull() && !plan.ActorType.IsUnknown() && plan.ActorType.ValueString() != client.ActorAccountID {
+		plan.ActorAccountID = types.StringNull()
+	}
+
+	plan.GeneratedTriggerJSON = types.StringUnknown()
+	plan.GeneratedComponentsJSON = types.StringUnknown()
+	if payloadKnown(req.Plan.Raw) {
+		trigger, td := r.resolveTriggerJSON(ctx, &plan)
+		components, cd := r.resolveComponentsJSON(ctx, &plan)
+		if !td.HasError() && !cd.HasError() {
+			plan.GeneratedTriggerJSON, plan.GeneratedComponentsJSON = generatedPayload(trigger, components)
+		}
+	}
+
+	// Metadata is stored as labels, so changing it changes labels too.
+	if !req.State.Raw.IsNull() {
+		var stateMetadata types.Map
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("metadata"), &stateMetadata)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.Metadata.Equal(stateMetadata) {
+			plan.Labels = types.ListUnknown(types.StringType)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || req.State.Raw.IsNull() {
+		return
+	}
+
+	// Refuse to update a system-owned rule unless explicitly allowed.
+	if plan.SystemOwned.ValueBool() && !plan.AllowSystem.ValueBool() && !resp.Plan.Raw.Equal(req.State.Raw) {
+		resp.Diagnostics.AddError("Refusing to update system-owned rule",
+			fmt.Sprintf("Rule %s is system-owned (authored by one of the provider's system_rule_authors). "+
+				"Changing it can break the Jira features that rely on it. Set allow_system_rule = true to update it anyway.", plan.ID.ValueString()))
+	}
+}
+
 func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan ruleResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	timeout, d := plan.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(d...)
+	r, ctx, cancel := r.withTimeout(ctx, timeout)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	trigger, diags := r.resolveTriggerJSON(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -218,12 +538,23 @@ func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	plan.GeneratedTriggerJSON, plan.GeneratedComponentsJSON = generatedPayload(trigger, components)
+	if !checkWritable(r.client, fmt.Sprintf("create rule %q", plan.Name.ValueString()), &resp.Diagnostics) {
+		return
+	}
 
+	// Create the rule in its desired state, so there's no window where it
+	// exists with the wrong one.
+	enabled := plan.Enabled.ValueBool()
 	createReq := client.CreateRuleRequest{
-		Name:       plan.Name.ValueString(),
-		ProjectID:  plan.ProjectID.ValueString(),
-		Trigger:    trigger,
-		Components: components,
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		ProjectID:   plan.ProjectID.ValueString(),
+		ScopeARIs:   toStringSlice(ctx, plan.ScopeARIs),
+		Trigger:     trigger,
+		Components:  components,
+		Enabled:     enabled,
+		Actor:       planActor(&plan, nil),
 	}
 
 	uuid, err := r.client.CreateRule(createReq)
@@ -232,39 +563,103 @@ func (r *ruleResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	// Set the rule state after creation if needed.
-	enabled := plan.Enabled.ValueBool()
-	if err := r.client.SetRuleState(uuid, enabled); err != nil {
-		resp.Diagnostics.AddError("Error setting rule state after creation", err.Error())
-		return
-	}
-
 	// Read back the created rule to populate computed fields (scope, state, etc.).
+	metadata := plan.Metadata
 	diags = r.readIntoModel(ctx, uuid, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
+		r.savePartialState(ctx, uuid, &plan, resp)
 		return
 	}
 
-	// Tag with managed-by:terraform.
-	r.syncManagedLabel(ctx, uuid, plan, &resp.Diagnostics)
+	// Fall back to setting the state explicitly if the API ignored the
+	// state in the create payload.
+	if plan.Enabled.ValueBool() != enabled {
+		if err := r.client.SetRuleState(uuid, enabled); err != nil {
+			resp.Diagnostics.AddError("Error setting rule state after creation", err.Error())
+			r.savePartialState(ctx, uuid, &plan, resp)
+			return
+		}
+		plan.Enabled = types.BoolValue(enabled)
+		if enabled {
+			plan.State = types.StringValue("ENABLED")
+		} else {
+			plan.State = types.StringValue("DISABLED")
+		}
+	}
 
-	// Re-read to pick up the label.
-	diags = r.readIntoModel(ctx, uuid, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// Tag with the managed and metadata labels; plan.Labels is updated in place.
+	if r.client.ManageLabel {
+		r.syncManagedLabel(ctx, uuid, &plan, &resp.Diagnostics)
 	}
+	r.syncMetadataLabels(ctx, uuid, metadata, types.MapNull(types.StringType), &plan, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// planActor returns the actor to send for plan, or nil to leave it to the
+// client's default on create (state is nil) or unchanged on update. Leaving it
+// unchanged keeps actors this resource can't express, such as SMART_VALUE.
+func planActor(plan, state *ruleResourceModel) *client.RuleActor {
+	if plan.ActorType.IsNull() || plan.ActorType.IsUnknown() {
+		return nil
+	}
+	if state != nil && plan.ActorType.Equal(state.ActorType) && plan.ActorAccountID.Equal(state.ActorAccountID) {
+		return nil
+	}
+	actor := &client.RuleActor{Type: plan.ActorType.ValueString()}
+	if actor.Type == client.ActorAccountID {
+		actor.Actor = plan.ActorAccountID.ValueString() // Empty if unknown: the client fills in its account.
+	}
+	return actor
+}
+
+// savePartialState records a rule that was created but not fully configured
+// or read back, so a failure after CreateRule doesn't orphan it. Terraform
+// taints it because Create also returns an error, and the next apply
+// reconciles it. Computed values are read back when possible and nulled
+// otherwise, since state can't hold unknowns.
+func (r *ruleResource) savePartialState(ctx context.Context, uuid string, plan *ruleResourceModel, resp *resource.CreateResponse) {
+	resp.Diagnostics.AddWarning("Rule saved to state after partial create",
+		fmt.Sprintf("Rule %s was created in Jira before the error above, so it has been saved to state (tainted) for the next apply to reconcile.", uuid))
+
+	plan.ID = types.StringValue(uuid)
+	if diags := r.readIntoModel(ctx, uuid, plan); diags.HasError() {
+		if plan.State.IsUnknown() {
+			plan.State = types.StringNull()
+		}
+		if plan.Scope.IsUnknown() {
+			plan.Scope = types.ListNull(types.StringType)
+		}
+		if plan.Labels.IsUnknown() {
+			plan.Labels = types.ListNull(types.StringType)
+		}
+		if plan.AuthorID.IsUnknown() {
+			plan.AuthorID = types.StringNull()
+		}
+		if plan.WebhookURL.IsUnknown() {
+			plan.WebhookURL = types.StringNull()
+		}
+		if plan.SystemOwned.IsUnknown() {
+			plan.SystemOwned = types.BoolNull()
+		}
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
 func (r *ruleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state ruleResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	timeout, d := state.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(d...)
+	r, ctx, cancel := r.withTimeout(ctx, timeout)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	diags := r.readIntoModel(ctx, state.ID.ValueString(), &state)
 	resp.Diagnostics.Append(diags...)
@@ -272,6 +667,16 @@ func (r *ruleResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// Backfill the generated payload for imported rules and state written before
+	// it existed, so the next plan doesn't show an update for a computed value.
+	if state.GeneratedTriggerJSON.IsNull() || state.GeneratedComponentsJSON.IsNull() {
+		trigger, td := r.resolveTriggerJSON(ctx, &state)
+		components, cd := r.resolveComponentsJSON(ctx, &state)
+		if !td.HasError() && !cd.HasError() {
+			state.GeneratedTriggerJSON, state.GeneratedComponentsJSON = generatedPayload(trigger, components)
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -287,6 +692,13 @@ func (r *ruleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	timeout, d := plan.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(d...)
+	r, ctx, cancel := r.withTimeout(ctx, timeout)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	uuid := state.ID.ValueString()
 
@@ -301,54 +713,86 @@ func (r *ruleResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	plan.GeneratedTriggerJSON, plan.GeneratedComponentsJSON = generatedPayload(trigger, components)
+	if !checkWritable(r.client, "update rule "+uuid, &resp.Diagnostics) {
+		return
+	}
 
 	updateReq := client.UpdateRuleRequest{
-		Name:       plan.Name.ValueString(),
-		Trigger:    trigger,
-		Components: components,
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		Trigger:     trigger,
+		Components:  components,
+		Actor:       planActor(&plan, &state),
+
+		PreserveComponentIDs: !plan.Recreate.ValueBool(),
+		ComponentIDSources:   r.componentIDSources(ctx, state.Components, plan.Components),
 	}
 
-	if err := r.client.UpdateRule(uuid, updateReq); err != nil {
+	rule, err := r.client.UpdateRule(uuid, updateReq)
+	if err != nil {
 		resp.Diagnostics.AddError("Error updating rule", err.Error())
 		return
 	}
 
-	// Handle enabled state change.
-	enabled := plan.Enabled.ValueBool()
-	if err := r.client.SetRuleState(uuid, enabled); err != nil {
-		resp.Diagnostics.AddError("Error setting rule state", err.Error())
-		return
+	// Handle enabled state change; the rule was just read, so its state is current.
+	wantState := "DISABLED"
+	if plan.Enabled.ValueBool() {
+		wantState = "ENABLED"
+	}
+	if rule.State != wantState {
+		if err := r.client.SetRuleState(uuid, plan.Enabled.ValueBool()); err != nil {
+			resp.Diagnostics.AddError("Error setting rule state", err.Error())
+			return
+		}
+		rule.State = wantState
 	}
 
-	// Read back the updated rule.
-	diags := r.readIntoModel(ctx, uuid, &plan)
+	// Use the rule as sent rather than reading it back. The API generates
+	// webhook tokens on save, so a changed trigger (webhook_url unknown) is
+	// read back in full; otherwise the URL is the prior one.
+	metadata := plan.Metadata
+	webhookURL := plan.WebhookURL
+	var diags diag.Diagnostics
+	if webhookURL.IsUnknown() {
+		diags = r.readIntoModel(ctx, uuid, &plan)
+	} else {
+		diags = r.ruleIntoModel(ctx, rule, &plan)
+		plan.WebhookURL = webhookURL
+	}
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Tag with managed-by:terraform.
-	r.syncManagedLabel(ctx, uuid, plan, &resp.Diagnostics)
-
-	// Re-read to pick up the label.
-	diags = r.readIntoModel(ctx, uuid, &plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// Tag with the managed and metadata labels; plan.Labels is updated in place.
+	if r.client.ManageLabel {
+		r.syncManagedLabel(ctx, uuid, &plan, &resp.Diagnostics)
 	}
+	r.syncMetadataLabels(ctx, uuid, metadata, state.Metadata, &plan, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func (r *ruleResource) Delete(_ context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+func (r *ruleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var state ruleResourceModel
-	resp.Diagnostics.Append(req.State.Get(context.Background(), &state)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, d := state.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(d...)
+	r, _, cancel := r.withTimeout(ctx, timeout)
+	defer cancel()
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	// No DELETE endpoint in the public API — disable the rule instead.
 	uuid := state.ID.ValueString()
+	if !checkWritable(r.client, "disable rule "+uuid+" on destroy", &resp.Diagnostics) {
+		return
+	}
 	if err := r.client.SetRuleState(uuid, false); err != nil {
 		resp.Diagnostics.AddError("Error disabling rule on destroy",
 			fmt.Sprintf("The Jira Automation API has no DELETE endpoint. Attempted to disable rule %s instead, but got error: %s", uuid, err.Error()))
@@ -362,30 +806,74 @@ func (r *ruleResource) Delete(_ context.Context, req resource.DeleteRequest, res
 func (r *ruleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	uuid := req.ID
 
-	var model ruleResourceModel
+	model := ruleResourceModel{Timeouts: nullTimeouts(ctx)}
 	diags := r.readIntoModel(ctx, uuid, &model)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Imports always land in trigger_json/components_json. Say what, if
+	// anything, stops the rule from moving to the structured blocks.
+	if reasons := r.fallbackReasons(ctx, &model); len(reasons) > 0 {
+		resp.Diagnostics.AddWarning("Rule imported as raw JSON",
+			fmt.Sprintf("Rule %s can't be converted to the structured trigger/components blocks:\n  - %s\n"+
+				"Keep trigger_json/components_json for these parts.", uuid, strings.Join(reasons, "\n  - ")))
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
+// fallbackReasons runs StructuredFallbackReasons over the raw JSON read into model.
+func (r *ruleResource) fallbackReasons(ctx context.Context, model *ruleResourceModel) []string {
+	var components []json.RawMessage
+	if err := json.Unmarshal([]byte(model.ComponentsJSON.ValueString()), &components); err != nil {
+		return []string{fmt.Sprintf("components: %s", err)}
+	}
+	return StructuredFallbackReasons(json.RawMessage(model.TriggerJSON.ValueString()), components, ctx, r.client.DebugLogPrefix)
+}
+
 // readIntoModel fetches a rule by UUID and populates the model.
 func (r *ruleResource) readIntoModel(ctx context.Context, uuid string, model *ruleResourceModel) diag.Diagnostics {
-	var diags diag.Diagnostics
-
 	rule, err := r.client.GetRule(uuid)
 	if err != nil {
+		var diags diag.Diagnostics
 		diags.AddError("Error reading rule", err.Error())
 		return diags
 	}
+	return r.ruleIntoModel(ctx, rule, model)
+}
+
+// ruleIntoModel populates the model from a rule already at hand.
+func (r *ruleResource) ruleIntoModel(ctx context.Context, rule *client.Rule, model *ruleResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
 
 	model.ID = types.StringValue(rule.UUID)
 	model.Name = types.StringValue(rule.Name)
+	model.Description = types.StringValue(rule.Description)
 	model.State = types.StringValue(rule.State)
 	model.Enabled = types.BoolValue(rule.State == "ENABLED")
+	model.AuthorID = types.StringValue(rule.AuthorAccountID)
+	model.ActorType = types.StringNull()
+	model.ActorAccountID = types.StringNull()
+	if rule.Actor != nil {
+		model.ActorType = types.StringValue(rule.Actor.Type)
+		if rule.Actor.Type == client.ActorAccountID {
+			model.ActorAccountID = types.StringValue(rule.Actor.Actor)
+		}
+	}
+	model.WebhookURL = triggerWebhookURL(rule.Trigger)
+	model.SystemOwned = types.BoolValue(rule.IsSystemOwned(r.client.SystemAuthors))
+	if model.AllowSystem.IsNull() {
+		model.AllowSystem = types.BoolValue(false) // Imported: match the schema default.
+	}
+	if model.Recreate.IsNull() {
+		model.Recreate = types.BoolValue(true)
+	}
+	if rule.IsSystemOwned(r.client.SystemAuthors) {
+		diags.AddWarning("System-owned rule",
+			fmt.Sprintf("Rule %s (%s) looks system-owned. Terraform will refuse to update it unless allow_system_rule = true.", rule.UUID, rule.Name))
+	}
 
 	// Scope
 	if len(rule.RuleScopeARIs) > 0 {
@@ -395,6 +883,12 @@ func (r *ruleResource) readIntoModel(ctx context.Context, uuid string, model *ru
 	} else {
 		model.Scope = types.ListNull(types.StringType)
 	}
+	// scope_aris stays as configured while it lists the same ARIs in any order.
+	if model.ScopeARIs.IsNull() {
+		model.ScopeARIs = types.ListNull(types.StringType) // Imported: typed for state.
+	} else if !sameStrings(toStringSlice(ctx, model.ScopeARIs), rule.RuleScopeARIs) {
+		model.ScopeARIs = model.Scope
+	}
 
 	// Labels — read-only, show whatever the API returns.
 	if len(rule.Labels) > 0 {
@@ -404,6 +898,7 @@ func (r *ruleResource) readIntoModel(ctx context.Context, uuid string, model *ru
 	} else {
 		model.Labels = types.ListNull(types.StringType)
 	}
+	model.Metadata = metadataFromLabels(ctx, model.Metadata, rule.Labels, r.client.ManagedLabelName)
 
 	// Trigger — if the user used the structured trigger block, parse the API
 	// response back into the trigger model. Otherwise, populate trigger_json.
@@ -415,27 +910,63 @@ func (r *ruleResource) readIntoModel(ctx context.Context, uuid string, model *ru
 		}
 		argsMap, d := types.MapValueFrom(ctx, types.StringType, args)
 		diags.Append(d...)
-		model.Trigger = &triggerModel{
+		when, err := parseWhen(rule.Trigger, r.client.ReverseAliases)
+		if err != nil {
+			diags.AddError("Error parsing trigger conditions from API", err.Error())
+			return diags
+		}
+		parsed := &triggerModel{
 			Type: types.StringValue(triggerType),
 			Args: argsMap,
+			When: when,
 		}
+		parsed.Args = equivalentTriggerArgs(ctx, model.Trigger, parsed)
+		parsed.When = sameAliasForm(model.Trigger.When, parsed.When, r.client.FieldAliases)
+		model.Trigger = parsed
 	} else {
 		triggerNorm, err := normalizeRawJSON(rule.Trigger)
 		if err != nil {
 			diags.AddError("Error normalizing trigger", err.Error())
 			return diags
 		}
-		model.TriggerJSON = jsontypes.NewNormalizedValue(triggerNorm)
+		triggerNorm, normalize, err := r.aliasRawJSON(triggerNorm, normalizeRawJSON)
+		if err != nil {
+			diags.AddError("Error applying field aliases to trigger", err.Error())
+			return diags
+		}
+		model.TriggerJSON = keepEquivalentJSON(model.TriggerJSON, triggerNorm, normalize, r.client.JSONIndent)
 	}
 
 	// Components — if the user used the structured components block, parse the API
 	// response back into component models. Otherwise, populate components_json.
 	if model.Components != nil {
-		parsed, err := ParseComponents(rule.Components, ctx, r.client.ReverseAliases)
+		parsed, err := ParseComponents(rule.Components, ctx, r.client.ReverseAliases, r.client.DebugLogPrefix)
 		if err != nil {
 			diags.AddError("Error parsing components from API", err.Error())
 			return diags
 		}
+		preserveEmptyBranches(model.Components, parsed)
+		preserveDefaultArgs(ctx, model.Components, parsed)
+		preserveRawJSON(model.Components, parsed)
+		preserveAliasForms(model.Components, parsed, r.client.FieldAliases)
+		preserveComponentKeys(model.Components, parsed)
+
+		if n := strayDebugLogs(rule.Components, r.client.DebugLogPrefix); n > 0 {
+			diags.AddWarning("Debug logs not recognized",
+				fmt.Sprintf("Rule %s has %d log action(s) that look like add_release_related_work debug logs, but not the complete set of %d directly before the webhook. "+
+					"They were probably edited or reordered in Jira, so they are read back as log components and the webhook's debug arg as unset. "+
+					"The next apply replaces them with what the config describes.", rule.UUID, n, debugLogCount))
+		}
+
+		if r.client.WebhookUser != "" && r.client.WebhookToken != "" {
+			expected := relatedWorkAuthHeader(r.client.WebhookUser, r.client.WebhookToken)
+			if n := foreignWebhookAuth(rule.Components, expected); n > 0 {
+				diags.AddWarning("Webhook credentials differ from provider config",
+					fmt.Sprintf("%d add_release_related_work webhook(s) in rule %s authenticate with credentials other than the provider's webhook_user/webhook_token. "+
+						"The next apply that changes this rule will replace them with the provider's credentials. "+
+						"Use components_json to keep the existing headers.", n, rule.UUID))
+			}
+		}
 		model.Components = parsed
 	} else {
 		componentsNorm, err := normalizeRawJSONArray(rule.Components)
@@ -443,7 +974,12 @@ func (r *ruleResource) readIntoModel(ctx context.Context, uuid string, model *ru
 			diags.AddError("Error normalizing components", err.Error())
 			return diags
 		}
-		model.ComponentsJSON = jsontypes.NewNormalizedValue(componentsNorm)
+		componentsNorm, normalize, err := r.aliasRawJSON(componentsNorm, normalizeComponentsJSON)
+		if err != nil {
+			diags.AddError("Error applying field aliases to components", err.Error())
+			return diags
+		}
+		model.ComponentsJSON = keepEquivalentJSON(model.ComponentsJSON, componentsNorm, normalize, r.client.JSONIndent)
 	}
 
 	return diags
@@ -471,10 +1007,30 @@ func (r *ruleResource) resolveTriggerJSON(ctx context.Context, model *ruleResour
 			diags.AddError("Error building trigger JSON", err.Error())
 			return nil, diags
 		}
-		return raw, diags
+		whenArgs, err := typesMapToStringMap(ctx, model.Trigger.When)
+		if err != nil {
+			diags.AddError("Error building trigger JSON", err.Error())
+			return nil, diags
+		}
+		raws, err := attachWhen([]json.RawMessage{raw}, resolveAliases(whenArgs, r.client.FieldAliases))
+		if err != nil {
+			diags.AddError("Error building trigger JSON", fmt.Sprintf("when: %s", err))
+			return nil, diags
+		}
+		return raws[0], diags
 	}
 
-	return json.RawMessage(model.TriggerJSON.ValueString()), diags
+	stripped, err := stripAPIIDsJSON(json.RawMessage(model.TriggerJSON.ValueString()))
+	if err != nil {
+		diags.AddError("Invalid trigger_json", err.Error())
+		return nil, diags
+	}
+	raw, err := r.resolveRawJSON(stripped)
+	if err != nil {
+		diags.AddError("Invalid trigger_json", err.Error())
+		return nil, diags
+	}
+	return raw, diags
 }
 
 // resolveComponentsJSON returns the components JSON from either the structured
@@ -483,7 +1039,7 @@ func (r *ruleResource) resolveComponentsJSON(ctx context.Context, model *ruleRes
 	var diags diag.Diagnostics
 
 	if model.Components != nil {
-		raws, err := BuildComponentsJSON(model.Components, r.client.CloudID, r.client.WebhookUser, r.client.WebhookToken, ctx, r.client.FieldAliases)
+		raws, err := BuildComponentsJSON(model.Components, r.client.CloudID, r.client.WebhookUser, r.client.WebhookToken, r.client.DebugLogPrefix, ctx, r.client.FieldAliases)
 		if err != nil {
 			diags.AddError("Error building components JSON", err.Error())
 			return nil, diags
@@ -496,22 +1052,168 @@ func (r *ruleResource) resolveComponentsJSON(ctx context.Context, model *ruleRes
 		diags.AddError("Invalid components_json", err.Error())
 		return nil, diags
 	}
+	for i, raw := range components {
+		if components[i], err = r.resolveRawJSON(raw); err != nil {
+			diags.AddError("Invalid components_json", fmt.Sprintf("component %d: %s", i, err))
+			return nil, diags
+		}
+	}
 	return components, diags
 }
 
+// componentIDSources pairs the API components plan's components build to
+// with those state's components were read from, matching keyed components by
+// key. It returns nil, for pairing by position, when there are no keys or
+// the spans can't be worked out.
+func (r *ruleResource) componentIDSources(ctx context.Context, prior, planned []componentModel) []int {
+	sources := componentIDSources(prior, planned)
+	if sources == nil {
+		return nil
+	}
+	c := r.client
+	priorSpans, err := componentSpans(prior, c.CloudID, c.WebhookUser, c.WebhookToken, c.DebugLogPrefix, ctx, c.FieldAliases)
+	if err != nil {
+		return nil
+	}
+	plannedSpans, err := componentSpans(planned, c.CloudID, c.WebhookUser, c.WebhookToken, c.DebugLogPrefix, ctx, c.FieldAliases)
+	if err != nil {
+		return nil
+	}
+	return rawIDSources(sources, priorSpans, plannedSpans)
+}
+
+// resolveRawJSON resolves field aliases in raw trigger_json/components_json
+// when resolve_aliases_in_json is enabled, and returns raw unchanged otherwise.
+func (r *ruleResource) resolveRawJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if !r.client.AliasRawJSON {
+		return raw, nil
+	}
+	return resolveAliasesInJSON(raw, r.client.FieldAliases)
+}
+
+// aliasRawJSON is the read-side counterpart of resolveRawJSON. With
+// resolve_aliases_in_json enabled it turns field IDs in apiNorm back into
+// aliases, and wraps normalize so prior values are compared in the same
+// alias form regardless of whether the config used aliases or field IDs.
+func (r *ruleResource) aliasRawJSON(apiNorm string, normalize func(json.RawMessage) (string, error)) (string, func(json.RawMessage) (string, error), error) {
+	if !r.client.AliasRawJSON {
+		return apiNorm, normalize, nil
+	}
+	canonical := func(raw json.RawMessage) (string, error) {
+		resolved, err := resolveAliasesInJSON(raw, r.client.FieldAliases)
+		if err != nil {
+			return "", err
+		}
+		aliased, err := unresolveAliasesInJSON(resolved, r.client.ReverseAliases)
+		if err != nil {
+			return "", err
+		}
+		return normalize(aliased)
+	}
+	aliased, err := canonical(json.RawMessage(apiNorm))
+	if err != nil {
+		return "", nil, err
+	}
+	return aliased, canonical, nil
+}
+
 // Helper functions
 
+// parseComponentsJSON validates a components_json string and splits it into
+// components, with the API-assigned ids stripped. JSON copied from the API
+// (e.g. a jsondecode()d rule) can then be sent as is; everything else the
+// user wrote, API enrichment included, is sent unchanged.
 func parseComponentsJSON(s string) ([]json.RawMessage, error) {
+	if err := validateComponentsJSON(s); err != nil {
+		return nil, err
+	}
 	var components []json.RawMessage
 	if err := json.Unmarshal([]byte(s), &components); err != nil {
 		return nil, fmt.Errorf("invalid components_json: %w", err)
 	}
+	for i, raw := range components {
+		stripped, err := stripAPIIDsJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid components_json: %w", err)
+		}
+		components[i] = stripped
+	}
 	return components, nil
 }
 
+// stripAPIIDsJSON removes the ids stripAPIIDs covers from raw JSON that is
+// about to be sent. Unlike normalizeRawJSON it keeps enrichment such as
+// eventFilters, which the user may have set on purpose.
+func stripAPIIDsJSON(raw json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	stripAPIIDs(v)
+	return json.Marshal(v)
+}
+
+// numericIDPattern matches Jira numeric IDs such as project IDs.
+var numericIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// metadataKeyPattern matches metadata keys, which can't contain the ':' that
+// separates key from value in their labels.
+var metadataKeyPattern = regexp.MustCompile(`^[^:]+$`)
+
+// errNoComponents explains why an empty components list is rejected before it
+// reaches the API, which otherwise fails with an opaque 400.
+const errNoComponents = "at least one component is required: Jira Automation rejects rules without components. " +
+	"For a trigger-only rule, add a log action as a placeholder."
+
+// requiredComponentKeys are the keys every trigger/component object must carry.
+var requiredComponentKeys = []string{"component", "type"}
+
+// validateComponentsJSON checks that s is a JSON array of objects, each with
+// the keys the API needs to identify a component.
+func validateComponentsJSON(s string) error {
+	var arr []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &arr); err != nil {
+		return fmt.Errorf("components_json must be a JSON array of component objects: %w", err)
+	}
+	if len(arr) == 0 {
+		return fmt.Errorf("components_json: %s", errNoComponents)
+	}
+	for i, raw := range arr {
+		if err := validateComponentObject(raw); err != nil {
+			return fmt.Errorf("components_json[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateTriggerJSON checks that s is a single JSON object with the keys the
+// API needs to identify a trigger.
+func validateTriggerJSON(s string) error {
+	if err := validateComponentObject(json.RawMessage(s)); err != nil {
+		return fmt.Errorf("trigger_json: %w", err)
+	}
+	return nil
+}
+
+func validateComponentObject(raw json.RawMessage) error {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+		return fmt.Errorf("must be a JSON object")
+	}
+	for _, key := range requiredComponentKeys {
+		v, ok := obj[key].(string)
+		if !ok || v == "" {
+			return fmt.Errorf("missing required string key %q", key)
+		}
+	}
+	return nil
+}
+
 // normalizeRawJSON round-trips raw JSON through interface{} for canonical output,
 // stripping API-assigned fields (id, parentId, conditionParentId) that aren't
-// part of the Terraform config.
+// part of the Terraform config. Object keys come out sorted at every depth,
+// including objects inside arrays such as webhook headers, since encoding/json
+// sorts map keys.
 func normalizeRawJSON(raw json.RawMessage) (string, error) {
 	var v interface{}
 	if err := json.Unmarshal(raw, &v); err != nil {
@@ -525,6 +1227,133 @@ func normalizeRawJSON(raw json.RawMessage) (string, error) {
 	return string(out), nil
 }
 
+// normalizeComponentsJSON applies normalizeRawJSONArray to a components_json string.
+func normalizeComponentsJSON(raw json.RawMessage) (string, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(raw, &raws); err != nil {
+		return "", err
+	}
+	return normalizeRawJSONArray(raws)
+}
+
+// keepEquivalentJSON returns prior when it normalizes to apiNorm, so API
+// enrichment (eventFilters, ids, ...) that the user chose to include in their
+// JSON doesn't show as drift. schemaVersion is ignored too, since the API may
+// upgrade a component's schema after create. This mirrors the structured path,
+// whose parsers ignore the same fields. Otherwise the normalized API value is
+// returned, indented when indent is set.
+func keepEquivalentJSON(prior jsontypes.Normalized, apiNorm string, normalize func(json.RawMessage) (string, error), indent bool) jsontypes.Normalized {
+	if !prior.IsNull() && !prior.IsUnknown() {
+		priorNorm, err := normalize(json.RawMessage(prior.ValueString()))
+		if err == nil && (priorNorm == apiNorm || withoutSchemaVersions(priorNorm) == withoutSchemaVersions(apiNorm)) {
+			return prior
+		}
+	}
+	if indent {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(apiNorm), "", "  "); err == nil {
+			return jsontypes.NewNormalizedValue(buf.String())
+		}
+	}
+	return jsontypes.NewNormalizedValue(apiNorm)
+}
+
+// withoutSchemaVersions returns norm with every schemaVersion key removed. It
+// is only used for comparison: values stored in state keep schemaVersion so
+// they can be sent back to the API as-is.
+func withoutSchemaVersions(norm string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(norm), &v); err != nil {
+		return norm
+	}
+	var strip func(v interface{})
+	strip = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			delete(t, "schemaVersion")
+			for _, e := range t {
+				strip(e)
+			}
+		case []interface{}:
+			for _, e := range t {
+				strip(e)
+			}
+		}
+	}
+	strip(v)
+	out, err := json.Marshal(v)
+	if err != nil {
+		return norm
+	}
+	return string(out)
+}
+
+// payloadAttributes are the plan attributes the API payload is built from.
+var payloadAttributes = []string{"project_id", "trigger", "trigger_json", "components", "components_json"}
+
+// payloadKnown reports whether every attribute feeding the payload is fully
+// known, so the plan-time build matches what apply will send.
+func payloadKnown(plan tftypes.Value) bool {
+	for _, name := range payloadAttributes {
+		v, err := plan.ApplyTerraform5AttributePathStep(tftypes.AttributeName(name))
+		if err != nil {
+			return false
+		}
+		if tv, ok := v.(tftypes.Value); !ok || !tv.IsFullyKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+// redactedValue replaces secure header values in the generated payload.
+const redactedValue = "(redacted)"
+
+// generatedPayload renders the trigger and components exactly as sent to the
+// API, indented for readability, with secure header values redacted.
+func generatedPayload(trigger json.RawMessage, components []json.RawMessage) (types.String, types.String) {
+	var buf bytes.Buffer
+	triggerOut := string(trigger)
+	if err := json.Indent(&buf, trigger, "", "  "); err == nil {
+		triggerOut = buf.String()
+	}
+
+	arr := make([]interface{}, 0, len(components))
+	for _, raw := range components {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return types.StringValue(triggerOut), types.StringUnknown()
+		}
+		redactSecureHeaders(v)
+		arr = append(arr, v)
+	}
+	out, err := json.MarshalIndent(arr, "", "  ")
+	if err != nil {
+		return types.StringValue(triggerOut), types.StringUnknown()
+	}
+	return types.StringValue(triggerOut), types.StringValue(string(out))
+}
+
+// redactSecureHeaders recursively replaces the value of every header marked
+// headerSecure, so credentials never appear in plan output or state.
+func redactSecureHeaders(v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if t["headerSecure"] == true {
+			if _, ok := t["value"]; ok {
+				t["value"] = redactedValue
+			}
+		}
+		for _, child := range t {
+			redactSecureHeaders(child)
+		}
+	case []interface{}:
+		for _, child := range t {
+			redactSecureHeaders(child)
+		}
+	}
+}
+
 func normalizeRawJSONArray(raws []json.RawMessage) (string, error) {
 	var arr []interface{}
 	for _, raw := range raws {
@@ -542,8 +1371,30 @@ func normalizeRawJSONArray(raws []json.RawMessage) (string, error) {
 	return string(out), nil
 }
 
+// stripAPIIDs recursively removes the structural ids the API assigns to a
+// component and its children and conditions. They belong to the rule they
+// were read from, so they're dropped before sending as well as on read.
+func stripAPIIDs(v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(m, "id")
+	delete(m, "parentId")
+	delete(m, "conditionParentId")
+	delete(m, "connectionId")
+	for _, key := range []string{"children", "conditions"} {
+		if nested, ok := m[key].([]interface{}); ok {
+			for _, child := range nested {
+				stripAPIIDs(child)
+			}
+		}
+	}
+}
+
 // stripAPIFields recursively removes API-assigned/enriched fields from JSON
 // so the normalized output matches the Terraform config (which doesn't include them).
+// It's for read-side comparison only: the send side strips just the ids.
 func stripAPIFields(v interface{}) {
 	m, ok := v.(map[string]interface{})
 	if !ok {
@@ -594,45 +1445,315 @@ func toStringSlice(ctx context.Context, list types.List) []string {
 	return strs
 }
 
-// syncManagedLabel tags the rule with managed-by:terraform via the internal API.
+func toStringMap(ctx context.Context, m types.Map) map[string]string {
+	if m.IsNull() || m.IsUnknown() {
+		return nil
+	}
+	var strs map[string]string
+	m.ElementsAs(ctx, &strs, false)
+	return strs
+}
+
+// syncManagedLabel tags the rule with the provider's managed label via the internal API.
 // Warns instead of failing if the label doesn't exist — the user must create it in the Jira UI.
-func (r *ruleResource) syncManagedLabel(ctx context.Context, uuid string, model ruleResourceModel, diags *diag.Diagnostics) {
-	scopes := toStringSlice(ctx, model.Scope)
-	if len(scopes) != 1 {
-		return // Global or multi-project — skip.
+func (r *ruleResource) syncManagedLabel(ctx context.Context, uuid string, model *ruleResourceModel, diags *diag.Diagnostics) {
+	projectID, err := labelProject(ctx, model)
+	if err != nil {
+		diags.AddWarning("Could not tag rule", fmt.Sprintf("Skipping the managed label for rule %s: %s.", uuid, err))
+		return
 	}
-	projectID := client.ExtractProjectID(scopes[0])
 	if projectID == "" {
 		return
 	}
+	labelName := r.client.ManagedLabelName
+	labels := toStringSlice(ctx, model.Labels)
+	if slices.Contains(labels, labelName) {
+		return // Already tagged (the common case on update).
+	}
 
-	// Look up the managed-by:terraform label. If it doesn't exist, warn the user.
-	labels, err := r.client.ListLabels(projectID)
+	// Look up the managed label (cached per project). If it doesn't exist, warn the user.
+	labelID, err := r.client.LabelID(projectID, labelName)
 	if err != nil {
 		diags.AddWarning("Could not list labels",
-			fmt.Sprintf("Could not list labels for project %s: %s. Create a 'managed-by:terraform' label in the Jira UI to tag managed rules.", projectID, err))
+			fmt.Sprintf("Could not list labels for project %s: %s. Create a '%s' label in the Jira UI to tag managed rules.", projectID, err, labelName))
+		return
+	}
+
+	if labelID == 0 {
+		diags.AddWarning(fmt.Sprintf("Label '%s' not found", labelName),
+			fmt.Sprintf("Create a label named '%s' in the Jira Automation UI to tag Terraform-managed rules. ", labelName)+
+				"Go to Project Settings → Automation → Labels to create it.")
+		return
+	}
+
+	if err := r.client.AddLabelToRule(projectID, uuid, labelID); err != nil {
+		diags.AddWarning(fmt.Sprintf("Could not tag rule with %s", labelName),
+			fmt.Sprintf("Failed to add %s label to rule %s: %s", labelName, uuid, err))
 		return
 	}
 
-	var labelID int
-	for _, l := range labels {
-		if l.Name == "managed-by:terraform" {
-			labelID = l.ID
-			break
+	// Record the label locally rather than re-reading the whole rule.
+	labelList, d := types.ListValueFrom(ctx, types.StringType, append(labels, labelName))
+	diags.Append(d...)
+	model.Labels = labelList
+}
+
+// labelProject returns the ID of the project whose labels the rule can use,
+// or "" if it has none: labels are per project, so global, multi-project,
+// site and other scopes have none to add.
+func labelProject(ctx context.Context, model *ruleResourceModel) (string, error) {
+	scopes := toStringSlice(ctx, model.Scope)
+	if len(scopes) != 1 {
+		return "", nil
+	}
+	scope, err := client.ParseScopeARI(scopes[0])
+	if err != nil {
+		return "", err
+	}
+	if scope.Kind != "project" {
+		return "", nil
+	}
+	return scope.ID, nil
+}
+
+// metadataLabelColor is the color of metadata labels the provider creates.
+// The API requires one, and metadata has no color of its own.
+const metadataLabelColor = "B300"
+
+// syncMetadataLabels makes the rule's key:value labels match metadata. It adds
+// a label per entry, creating it in the project if needed, and removes labels
+// whose key is in metadata or prior (the metadata in state) but whose value
+// isn't the one in metadata, so dropped keys and changed values are cleaned
+// up. model.Labels and model.Metadata are updated in place; entries that
+// couldn't be synced are left out of model.Metadata so the next plan retries
+// them.
+func (r *ruleResource) syncMetadataLabels(ctx context.Context, uuid string, metadata, prior types.Map, model *ruleResourceModel, diags *diag.Diagnostics) {
+	want := toStringMap(ctx, metadata)
+	had := toStringMap(ctx, prior)
+	labels := toStringSlice(ctx, model.Labels)
+	if len(want) == 0 && len(had) == 0 {
+		model.Metadata = metadataFromLabels(ctx, metadata, labels, r.client.ManagedLabelName)
+		return
+	}
+
+	projectID, err := labelProject(ctx, model)
+	if err != nil || projectID == "" {
+		reason := "metadata is stored as labels, which only project-scoped rules have"
+		if err != nil {
+			reason = err.Error()
+		}
+		diags.AddWarning("Could not set metadata", fmt.Sprintf("Skipping metadata for rule %s: %s.", uuid, reason))
+		model.Metadata = metadataFromLabels(ctx, metadata, labels, r.client.ManagedLabelName)
+		return
+	}
+
+	kept := labels[:0:0]
+	for _, label := range labels {
+		key, value, ok := strings.Cut(label, ":")
+		wantValue, wanted := want[key]
+		_, hadKey := had[key]
+		if !ok || label == r.client.ManagedLabelName || (!wanted && !hadKey) || (wanted && value == wantValue) {
+			kept = append(kept, label)
+			continue
+		}
+		if err := r.removeLabel(projectID, uuid, label); err != nil {
+			diags.AddWarning(fmt.Sprintf("Could not remove label %s", label),
+				fmt.Sprintf("Failed to remove %s from rule %s: %s", label, uuid, err))
+			kept = append(kept, label)
+		}
+	}
+	labels = kept
+
+	for _, key := range slices.Sorted(maps.Keys(want)) {
+		label := key + ":" + want[key]
+		if slices.Contains(labels, label) {
+			continue
+		}
+		if err := r.addLabel(projectID, uuid, label); err != nil {
+			diags.AddWarning(fmt.Sprintf("Could not tag rule with %s", label),
+				fmt.Sprintf("Failed to add %s to rule %s: %s", label, uuid, err))
+			continue
+		}
+		labels = append(labels, label)
+	}
+
+	// Record the labels locally rather than re-reading the whole rule.
+	model.Labels = types.ListNull(types.StringType)
+	if len(labels) > 0 {
+		labelList, d := types.ListValueFrom(ctx, types.StringType, labels)
+		diags.Append(d...)
+		model.Labels = labelList
+	}
+	model.Metadata = metadataFromLabels(ctx, metadata, labels, r.client.ManagedLabelName)
+}
+
+// addLabel adds the named label to a rule, creating it in the project first
+// if it doesn't exist.
+func (r *ruleResource) addLabel(projectID, uuid, name string) error {
+	labelID, err := r.client.LabelID(projectID, name)
+	if err != nil {
+		return err
+	}
+	if labelID == 0 {
+		created, err := r.client.CreateLabel(projectID, client.CreateLabelRequest{Name: name, Color: metadataLabelColor})
+		if err != nil {
+			return err
 		}
+		labelID = created.ID
 	}
+	return r.client.AddLabelToRule(projectID, uuid, labelID)
+}
 
+// removeLabel removes the named label from a rule, leaving it in the project.
+func (r *ruleResource) removeLabel(projectID, uuid, name string) error {
+	labelID, err := r.client.LabelID(projectID, name)
+	if err != nil {
+		return err
+	}
 	if labelID == 0 {
-		diags.AddWarning("Label 'managed-by:terraform' not found",
-			"Create a label named 'managed-by:terraform' in the Jira Automation UI to tag Terraform-managed rules. "+
-				"Go to Project Settings → Automation → Labels to create it.")
+		return fmt.Errorf("label not found in project %s", projectID)
+	}
+	return r.client.RemoveLabelFromRule(projectID, uuid, labelID)
+}
+
+// metadataFromLabels reads the configured metadata keys back from the rule's
+// key:value labels. Only configured keys are read, since other labels with a
+// colon, such as the managed label, aren't metadata. Keys without a label are
+// left out, so the plan shows them being added.
+func metadataFromLabels(ctx context.Context, configured types.Map, labels []string, managedLabel string) types.Map {
+	if configured.IsNull() || configured.IsUnknown() {
+		return types.MapNull(types.StringType)
+	}
+	want := toStringMap(ctx, configured)
+	got := make(map[string]string, len(want))
+	for _, label := range labels {
+		key, value, ok := strings.Cut(label, ":")
+		wantValue, wanted := want[key]
+		if !ok || !wanted || label == managedLabel {
+			continue
+		}
+		if _, seen := got[key]; !seen || value == wantValue {
+			got[key] = value
+		}
+	}
+	values := make(map[string]attr.Value, len(got))
+	for key, value := range got {
+		values[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, values)
+}
+
+// projectIDRequiresReplace replaces the rule when project_id changes, since
+// the scope is only set on create: PUT /rule/{uuid}/rule-scope is limited to
+// global admins, which the provider's user usually isn't. It compares against
+// the project in the rule's scope rather than the prior project_id, which is
+// null after import, so adding project_id to an imported rule that's already
+// in that project doesn't recreate it. Moving from project_id to scope_aris is left to
+// scopeARIsRequiresReplace.
+func projectIDRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	if req.PlanValue.IsNull() {
+		var scopeARIs types.List
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("scope_aris"), &scopeARIs)...)
+		if resp.Diagnostics.HasError() || !scopeARIs.IsNull() {
+			return
+		}
+	}
+
+	var scope types.List
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("scope"), &scope)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := r.client.AddLabelToRule(projectID, uuid, labelID); err != nil {
-		diags.AddWarning("Could not tag rule with managed-by:terraform",
-			fmt.Sprintf("Failed to add managed-by:terraform label to rule %s: %s", uuid, err))
+	current := ""
+	if scopes := toStringSlice(ctx, scope); len(scopes) == 1 {
+		current = client.ExtractProjectID(scopes[0])
 	}
+	resp.RequiresReplace = req.PlanValue.IsUnknown() || req.PlanValue.ValueString() != current
+}
+
+// --- scope_aris ---
+
+// scopeARIPattern loosely matches a scope ARI; the API validates the rest.
+var scopeARIPattern = regexp.MustCompile(`^ari:cloud:[a-z-]+:`)
+
+// scopeARIsRequiresReplace replaces the rule when scope_aris lists other ARIs
+// than its scope, for the same global-admin reason as projectIDRequiresReplace.
+// As with project_id, it compares against the scope itself, so adding
+// scope_aris that match an imported rule's scope doesn't recreate it.
+// Removing scope_aris leaves the rule as it is.
+func scopeARIsRequiresReplace(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+	if req.PlanValue.IsNull() {
+		return
+	}
+	var scope types.List
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("scope"), &scope)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.RequiresReplace = req.PlanValue.IsUnknown() || !sameStrings(toStringSlice(ctx, req.PlanValue), toStringSlice(ctx, scope))
+}
+
+// sameStrings reports whether a and b hold the same strings, ignoring order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// --- webhook_url ---
+
+// incomingWebhookURLBase is the prefix of an incoming-webhook trigger's
+// callback URL; the trigger's webhookToken completes it.
+const incomingWebhookURLBase = "https://automation.atlassian.com/pro/hooks/"
+
+// triggerWebhookURL returns the callback URL of an incoming-webhook trigger,
+// built from the webhookToken in its value, or null if the trigger has none.
+// The API doesn't return the URL itself.
+func triggerWebhookURL(trigger json.RawMessage) types.String {
+	var t struct {
+		Type  string `json:"type"`
+		Value struct {
+			WebhookToken string `json:"webhookToken"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(trigger, &t); err != nil || t.Type != "jira.incoming.webhook" || t.Value.WebhookToken == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(incomingWebhookURLBase + t.Value.WebhookToken)
+}
+
+// webhookURLModifier keeps the prior webhook_url while the trigger is
+// unchanged, so updates don't show it as known after apply. A changed trigger
+// may gain, lose, or regenerate the URL, so it stays unknown then.
+type webhookURLModifier struct{}
+
+func (m webhookURLModifier) Description(_ context.Context) string {
+	return "Uses the prior webhook_url unless the trigger changes."
+}
+
+func (m webhookURLModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m webhookURLModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+	for _, name := range []string{"trigger", "trigger_json"} {
+		p := path.Root(name)
+		var planned, prior attr.Value
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, p, &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, p, &prior)...)
+		if resp.Diagnostics.HasError() || !planned.Equal(prior) {
+			return
+		}
+	}
+	resp.PlanValue = req.StateValue
 }
 
 // --- State plan modifier (derived from enabled) ---
@@ -655,7 +1776,13 @@ func (m stateFromEnabledModifier) PlanModifyString(ctx context.Context, req plan
 		return
 	}
 
-	if enabled.IsUnknown() || enabled.IsNull() {
+	if enabled.IsUnknown() {
+		// The prior state may be stale (e.g. the rule was toggled in the UI),
+		// so don't promise it; apply sets state from the final enabled value.
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+	if enabled.IsNull() {
 		// Fall back to prior state if available.
 		if !req.StateValue.IsNull() {
 			resp.PlanValue = req.StateValue
@@ -670,3 +1797,190 @@ func (m stateFromEnabledModifier) PlanModifyString(ctx context.Context, req plan
 	}
 }
 
+// --- JSON shape validators ---
+
+// componentsJSONValidator rejects components_json values that aren't an array
+// of component objects, so the mistake surfaces at plan rather than apply.
+type componentsJSONValidator struct{}
+
+func (v componentsJSONValidator) Description(_ context.Context) string {
+	return "Validates that the value is a JSON array of objects, each with component and type keys."
+}
+
+func (v componentsJSONValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v componentsJSONValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := validateComponentsJSON(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid components_json", err.Error())
+	}
+}
+
+// triggerJSONValidator rejects trigger_json values that aren't a single trigger object.
+type triggerJSONValidator struct{}
+
+func (v triggerJSONValidator) Description(_ context.Context) string {
+	return "Validates that the value is a JSON object with component and type keys."
+}
+
+func (v triggerJSONValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v triggerJSONValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := validateTriggerJSON(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid trigger_json", err.Error())
+	}
+}
+
+// nonEmptyComponentsValidator rejects an empty components list at plan time.
+type nonEmptyComponentsValidator struct{}
+
+func (v nonEmptyComponentsValidator) Description(_ context.Context) string {
+	return "Validates that the list contains at least one component."
+}
+
+func (v nonEmptyComponentsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nonEmptyComponentsValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if len(req.ConfigValue.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Empty components", errNoComponents)
+	}
+}
+
+// commentVisibilityValidator rejects comment actions, at the top level or in
+// then/else, whose visibility args would post a public comment or be refused
+// at apply. Args that aren't known yet are checked at apply instead.
+type commentVisibilityValidator struct{}
+
+func (v commentVisibilityValidator) Description(_ context.Context) string {
+	return "Validates the visibility args of comment actions."
+}
+
+func (v commentVisibilityValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v commentVisibilityValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	check := func(p path.Path, what string, obj types.Object) {
+		typ, ok := obj.Attributes()["type"].(types.String)
+		if !ok || typ.ValueString() != "comment" {
+			return
+		}
+		args, ok := obj.Attributes()["args"].(types.Map)
+		if !ok || args.IsNull() || args.IsUnknown() {
+			return
+		}
+		known := map[string]string{}
+		for _, name := range []string{"visibility_type", "visibility_value"} {
+			str, ok := args.Elements()[name].(types.String)
+			if !ok {
+				continue
+			}
+			if str.IsUnknown() {
+				return
+			}
+			known[name] = str.ValueString()
+		}
+		if err := commentVisibilityError(known); err != nil {
+			resp.Diagnostics.AddAttributeError(p.AtName("args"), "Invalid comment visibility", fmt.Sprintf("%s: %s.", what, err))
+		}
+	}
+	for i, elem := range req.ConfigValue.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok {
+			continue
+		}
+		p := req.Path.AtListIndex(i)
+		check(p, fmt.Sprintf("component %d", i), obj)
+		for _, branch := range []string{"then", "else"} {
+			actions, ok := obj.Attributes()[branch].(types.List)
+			if !ok || actions.IsNull() || actions.IsUnknown() {
+				continue
+			}
+			for j, a := range actions.Elements() {
+				if action, ok := a.(types.Object); ok {
+					check(p.AtName(branch).AtListIndex(j), fmt.Sprintf("component %d, %s action %d", i, branch, j), action)
+				}
+			}
+		}
+	}
+}
+
+// smartValueBracesValidator warns about arg values with unbalanced {{ and }},
+// which the API silently treats as literal text. It only warns because some
+// legitimate values (e.g. inline JSON) contain braces.
+type smartValueBracesValidator struct{}
+
+func (v smartValueBracesValidator) Description(_ context.Context) string {
+	return "Warns when a value has unbalanced {{ and }} smart value braces."
+}
+
+func (v smartValueBracesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v smartValueBracesValidator) ValidateMap(_ context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	for _, k := range slices.Sorted(maps.Keys(req.ConfigValue.Elements())) {
+		str, ok := req.ConfigValue.Elements()[k].(types.String)
+		if !ok || str.IsNull() || str.IsUnknown() {
+			continue
+		}
+		if problem := unbalancedSmartValue(str.ValueString()); problem != "" {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtMapKey(k), "Unbalanced smart value braces",
+				fmt.Sprintf("%s in %q. Jira treats a malformed smart value as literal text.", problem, str.ValueString()))
+		}
+	}
+}
+
+// uniqueComponentKeysValidator rejects duplicate component keys.
+type uniqueComponentKeysValidator struct{}
+
+func (v uniqueComponentKeysValidator) Description(_ context.Context) string {
+	return "Validates that component keys are unique."
+}
+
+func (v uniqueComponentKeysValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v uniqueComponentKeysValidator) ValidateList(_ context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	seen := map[string]int{}
+	for i, elem := range req.ConfigValue.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok {
+			continue
+		}
+		key, ok := obj.Attributes()["key"].(types.String)
+		if !ok || key.IsNull() || key.IsUnknown() {
+			continue
+		}
+		if first, dup := seen[key.ValueString()]; dup {
+			resp.Diagnostics.AddAttributeError(req.Path.AtListIndex(i).AtName("key"), "Duplicate component key",
+				fmt.Sprintf("key %q is already used by component %d.", key.ValueString(), first))
+			continue
+		}
+		seen[key.ValueString()] = i
+	}
+}
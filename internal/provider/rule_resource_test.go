@@ -1,12 +1,41 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 // testAccCheckRuleResourceDestroy verifies that all test rules are DISABLED
@@ -48,6 +77,7 @@ func TestAccRuleResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("jira-automation_rule.test", "enabled", "true"),
 					resource.TestCheckResourceAttr("jira-automation_rule.test", "state", "ENABLED"),
 					resource.TestCheckResourceAttrSet("jira-automation_rule.test", "scope.#"),
+					resource.TestCheckResourceAttrSet("jira-automation_rule.test", "author_account_id"),
 				),
 			},
 		},
@@ -129,6 +159,48 @@ func TestAccRuleResource_disableEnable(t *testing.T) {
 	})
 }
 
+// TestAccRuleResource_enabledDrift disables the rule outside Terraform and
+// checks that the next plan shows the drift and apply re-enables it.
+func TestAccRuleResource_enabledDrift(t *testing.T) {
+	var uuid string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRuleResourceConfig_enabled("tf-acc-enabled-drift", true),
+				Check: func(s *terraform.State) error {
+					uuid = s.RootModule().Resources["jira-automation_rule.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				PreConfig: func() {
+					c, err := testAccNewClient()
+					if err != nil {
+						t.Fatalf("creating client: %v", err)
+					}
+					if err := c.SetRuleState(uuid, false); err != nil {
+						t.Fatalf("disabling rule out of band: %v", err)
+					}
+				},
+				Config: testAccRuleResourceConfig_enabled("tf-acc-enabled-drift", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jira-automation_rule.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("jira-automation_rule.test", tfjsonpath.New("state"), knownvalue.StringExact("ENABLED")),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "state", "ENABLED"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccRuleResource_destroyDisables(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
@@ -145,6 +217,49 @@ func TestAccRuleResource_destroyDisables(t *testing.T) {
 	})
 }
 
+// TestAccRuleResource_projectIDChange verifies that moving a rule to another
+// project replaces it (re-scoping in place needs a global admin) instead of
+// silently keeping the old scope.
+func TestAccRuleResource_projectIDChange(t *testing.T) {
+	var firstID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithSecondProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRuleResourceConfig_project("tf-acc-project-change", os.Getenv("JIRA_TEST_PROJECT_ID")),
+				Check: func(s *terraform.State) error {
+					firstID = s.RootModule().Resources["jira-automation_rule.test"].Primary.ID
+					return nil
+				},
+			},
+			{
+				Config: testAccRuleResourceConfig_project("tf-acc-project-change", os.Getenv("JIRA_TEST_PROJECT_ID_2")),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("jira-automation_rule.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "project_id", os.Getenv("JIRA_TEST_PROJECT_ID_2")),
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources["jira-automation_rule.test"]
+						if rs.Primary.ID == firstID {
+							return fmt.Errorf("rule %s was updated in place, expected a replacement", firstID)
+						}
+						want := ":project/" + os.Getenv("JIRA_TEST_PROJECT_ID_2")
+						if got := rs.Primary.Attributes["scope.0"]; !strings.HasSuffix(got, want) {
+							return fmt.Errorf("scope.0: got %q, want suffix %q", got, want)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func TestAccRuleResource_import(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
@@ -202,6 +317,12 @@ func TestAccRuleResource_componentsJSON(t *testing.T) {
 					resource.TestCheckResourceAttr("jira-automation_rule.test", "name", "tf-acc-components-json"),
 				),
 			},
+			{
+				// The API's enrichment of the log action must not show as drift.
+				Config:             testAccRuleResourceConfig_componentsJSON("tf-acc-components-json"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
 		},
 	})
 }
@@ -222,19 +343,53 @@ func TestAccRuleResource_conditionThenElse(t *testing.T) {
 	})
 }
 
+func TestAccRuleResource_conditionEmptyElse(t *testing.T) {
+	config := testAccRuleResourceConfig_conditionEmptyElse("tf-acc-condition-empty-else")
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.then.#", "1"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.else.#", "0"),
+				),
+			},
+			{
+				// Re-applying the same config must produce an empty plan.
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
 func TestAccRuleResource_fieldAliases(t *testing.T) {
+	config := testAccRuleResourceConfig_aliases("tf-acc-aliases")
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		CheckDestroy:             testAccCheckRuleResourceDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccRuleResourceConfig_aliases("tf-acc-aliases"),
+				Config: config,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("jira-automation_rule.test", "name", "tf-acc-aliases"),
 					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.type", "log"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.args.message", "tf-acc-test: alias={{issue.my_status}}"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.1.args.message", "tf-acc-test: id={{issue.status}}"),
 				),
 			},
+			{
+				// The API stores field IDs; reading them back must restore the
+				// configured form, alias or field ID, so re-applying is a no-op.
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
 		},
 	})
 }
@@ -273,9 +428,1401 @@ func TestAccRuleResource_addReleaseRelatedWorkDebug(t *testing.T) {
 	})
 }
 
+func TestAccRuleResource_conditionDebugWebhook(t *testing.T) {
+	config := testAccRuleResourceConfig_conditionDebugWebhook("tf-acc-condition-debug")
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithWebhook(t); testAccPreCheckWithProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.then.#", "1"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.then.0.type", "add_release_related_work"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.then.0.args.debug", "true"),
+					resource.TestCheckResourceAttr("jira-automation_rule.test", "components.0.else.#", "1"),
+				),
+			},
+			{
+				// The debug logs inside the IF block fold back into debug = "true".
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
+// --- JSON shape validation ---
+
+func TestValidateComponentsJSON(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", `[{"component":"ACTION","type":"codebarrel.action.log","value":"hi"}]`, ""},
+		{"empty array", `[]`, "at least one component is required"},
+		{"single object", `{"component":"ACTION","type":"codebarrel.action.log"}`, "must be a JSON array"},
+		{"non-object element", `[{"component":"ACTION","type":"codebarrel.action.log"}, "oops"]`, "components_json[1]: must be a JSON object"},
+		{"missing type", `[{"component":"ACTION"}]`, `components_json[0]: missing required string key "type"`},
+		{"missing component", `[{"type":"codebarrel.action.log"}]`, `components_json[0]: missing required string key "component"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateComponentsJSON(tc.input)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error %q should contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTriggerJSON(t *testing.T) {
+	if err := validateTriggerJSON(`{"component":"TRIGGER","type":"jira.issue.event.trigger:transitioned"}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := validateTriggerJSON(`[{"component":"TRIGGER","type":"jira.issue.event.trigger:transitioned"}]`); err == nil {
+		t.Fatal("expected error for array trigger_json")
+	}
+	if err := validateTriggerJSON(`{"component":"TRIGGER"}`); err == nil {
+		t.Fatal("expected error for trigger_json missing type")
+	}
+}
+
+func TestKeepEquivalentJSON_Trigger(t *testing.T) {
+	// The structured builder emits the API-enriched fields; a user switching to
+	// trigger_json with that payload must not see drift once the API echoes it.
+	built, err := BuildTriggerJSON("status_transition", map[string]string{
+		"from_status": "To Do",
+		"to_status":   "Done",
+	}, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	var api map[string]interface{}
+	if err := json.Unmarshal(built, &api); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	api["id"] = "42"
+	apiRaw, _ := json.Marshal(api)
+	apiNorm, err := normalizeRawJSON(apiRaw)
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+
+	prior := jsontypes.NewNormalizedValue(string(built))
+	got := keepEquivalentJSON(prior, apiNorm, normalizeRawJSON, false)
+	if got.ValueString() != prior.ValueString() {
+		t.Errorf("expected prior value to be kept, got %s", got.ValueString())
+	}
+
+	changed := jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"other"}`)
+	got = keepEquivalentJSON(changed, apiNorm, normalizeRawJSON, false)
+	if got.ValueString() != apiNorm {
+		t.Errorf("expected API value on real drift, got %s", got.ValueString())
+	}
+
+	got = keepEquivalentJSON(jsontypes.NewNormalizedNull(), apiNorm, normalizeRawJSON, false)
+	if got.ValueString() != apiNorm {
+		t.Errorf("expected API value on import, got %s", got.ValueString())
+	}
+}
+
+func TestKeepEquivalentJSON_Components(t *testing.T) {
+	prior := jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"codebarrel.action.log","value":"hi","children":[],"conditions":[],"connectionId":null}]`)
+	apiNorm, err := normalizeRawJSONArray([]json.RawMessage{
+		json.RawMessage(`{"id":"7","parentId":"1","component":"ACTION","type":"codebarrel.action.log","value":"hi"}`),
+	})
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+
+	got := keepEquivalentJSON(prior, apiNorm, normalizeComponentsJSON, false)
+	if got.ValueString() != prior.ValueString() {
+		t.Errorf("expected prior value to be kept, got %s", got.ValueString())
+	}
+}
+
+func TestKeepEquivalentJSON_LogActionEcho(t *testing.T) {
+	// The components_json acceptance config, and the API's echo of it after
+	// create: ids, null parents and connection, empty containers.
+	prior := jsontypes.NewNormalizedValue(`[{"component":"ACTION","schemaVersion":1,"type":"codebarrel.action.log","value":"tf-acc-test: x via components_json"}]`)
+	echo := json.RawMessage(`{"id":"123","component":"ACTION","parentId":null,"conditionParentId":null,"schemaVersion":1,` +
+		`"type":"codebarrel.action.log","value":"tf-acc-test: x via components_json","children":[],"conditions":[],"connectionId":null}`)
+
+	apiNorm, err := normalizeRawJSONArray([]json.RawMessage{echo})
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+	if got := keepEquivalentJSON(prior, apiNorm, normalizeComponentsJSON, false); got.ValueString() != prior.ValueString() {
+		t.Errorf("echo shows as drift: got %s", got.ValueString())
+	}
+
+	// What the provider sends for the config must also match the echo.
+	sent, err := parseComponentsJSON(prior.ValueString())
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if sentNorm, _ := normalizeRawJSONArray(sent); sentNorm != apiNorm {
+		t.Errorf("sent %s, API normalizes to %s", sentNorm, apiNorm)
+	}
+}
+
+func TestKeepEquivalentJSON_NestedKeyOrder(t *testing.T) {
+	// The API echoes a webhook action with the keys of each header object, and
+	// of the nested condition, in a different order than the config.
+	prior := jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"jira.issue.outgoing.webhook","schemaVersion":1,` +
+		`"value":{"url":"https://example.com/hook","method":"POST","headers":[` +
+		`{"name":"X-Token","value":"abc","headerSecure":false},{"name":"X-Source","value":"jira","headerSecure":false}]},` +
+		`"conditions":[{"component":"CONDITION","type":"jira.issue.condition","schemaVersion":3,"value":{"operator":"EQUALS","selectedField":{"type":"ID","value":"status"}}}]}]`)
+	echo := json.RawMessage(`{"id":"7","schemaVersion":1,"type":"jira.issue.outgoing.webhook","component":"ACTION",` +
+		`"value":{"headers":[{"headerSecure":false,"value":"abc","name":"X-Token"},{"value":"jira","headerSecure":false,"name":"X-Source"}],` +
+		`"method":"POST","url":"https://example.com/hook"},"children":[],` +
+		`"conditions":[{"value":{"selectedField":{"value":"status","type":"ID"},"operator":"EQUALS"},"type":"jira.issue.condition","schemaVersion":3,"component":"CONDITION","id":"8"}]}`)
+
+	apiNorm, err := normalizeRawJSONArray([]json.RawMessage{echo})
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+	priorNorm, err := normalizeComponentsJSON(json.RawMessage(prior.ValueString()))
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+	if priorNorm != apiNorm {
+		t.Errorf("config normalizes to %s, API to %s", priorNorm, apiNorm)
+	}
+	if got := keepEquivalentJSON(prior, apiNorm, normalizeComponentsJSON, false); got.ValueString() != prior.ValueString() {
+		t.Errorf("key order shows as drift: got %s", got.ValueString())
+	}
+}
+
+func TestKeepEquivalentJSON_SchemaVersionBump(t *testing.T) {
+	// The API upgraded the comment action from schemaVersion 2 to 3 after create.
+	built, err := buildComment(map[string]string{"message": "hi"}, "", "", "")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	bumped := strings.Replace(string(built), `"schemaVersion":2`, `"schemaVersion":3`, 1)
+	if bumped == string(built) {
+		t.Fatal("test setup: schemaVersion 2 not found in built comment")
+	}
+	apiNorm, err := normalizeRawJSONArray([]json.RawMessage{json.RawMessage(bumped)})
+	if err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+
+	prior := jsontypes.NewNormalizedValue("[" + string(built) + "]")
+	if got := keepEquivalentJSON(prior, apiNorm, normalizeComponentsJSON, false); got.ValueString() != prior.ValueString() {
+		t.Errorf("schemaVersion bump should not drift, got %s", got.ValueString())
+	}
+
+	// Without a matching prior (e.g. import), the API's schemaVersion is kept.
+	if got := keepEquivalentJSON(jsontypes.NewNormalizedNull(), apiNorm, normalizeComponentsJSON, false); !strings.Contains(got.ValueString(), `"schemaVersion":3`) {
+		t.Errorf("expected stored value to keep schemaVersion 3, got %s", got.ValueString())
+	}
+
+	// The structured path reads the bumped action back to the same args.
+	if args, err := parseComment(json.RawMessage(bumped)); err != nil || args["message"] != "hi" || len(args) != 1 {
+		t.Errorf("parsed bumped comment: got %v, %v", args, err)
+	}
+
+	// Other differences still drift.
+	changed := strings.Replace(bumped, `"hi"`, `"bye"`, 1)
+	apiNorm, _ = normalizeRawJSONArray([]json.RawMessage{json.RawMessage(changed)})
+	if got := keepEquivalentJSON(prior, apiNorm, normalizeComponentsJSON, false); got.ValueString() == prior.ValueString() {
+		t.Error("a changed message must not be treated as equivalent")
+	}
+}
+
+func TestKeepEquivalentJSON_Indent(t *testing.T) {
+	apiNorm := `{"component":"TRIGGER","type":"jira.manual.trigger","value":{"groups":[]}}`
+
+	got := keepEquivalentJSON(jsontypes.NewNormalizedNull(), apiNorm, normalizeRawJSON, true)
+	if !strings.Contains(got.ValueString(), "\n  \"component\"") {
+		t.Errorf("expected indented JSON, got %s", got.ValueString())
+	}
+
+	// Indented and compact forms must still compare equal for plan.
+	equal, diags := got.StringSemanticEquals(context.Background(), jsontypes.NewNormalizedValue(apiNorm))
+	if diags.HasError() {
+		t.Fatalf("semantic equals: %v", diags)
+	}
+	if !equal {
+		t.Error("indented and compact JSON should be semantically equal")
+	}
+
+	// A prior value that matches keeps its own formatting.
+	prior := jsontypes.NewNormalizedValue(apiNorm)
+	if got := keepEquivalentJSON(prior, apiNorm, normalizeRawJSON, true); got.ValueString() != apiNorm {
+		t.Errorf("expected prior value to be kept, got %s", got.ValueString())
+	}
+}
+
+func TestUniqueComponentKeysValidator(t *testing.T) {
+	objType := map[string]attr.Type{"key": types.StringType}
+	obj := func(key attr.Value) attr.Value {
+		return types.ObjectValueMust(objType, map[string]attr.Value{"key": key})
+	}
+	list := func(elems ...attr.Value) types.List {
+		return types.ListValueMust(types.ObjectType{AttrTypes: objType}, elems)
+	}
+
+	cases := map[string]struct {
+		list    types.List
+		wantErr bool
+	}{
+		"unique":    {list(obj(types.StringValue("a")), obj(types.StringValue("b"))), false},
+		"no keys":   {list(obj(types.StringNull()), obj(types.StringNull())), false},
+		"duplicate": {list(obj(types.StringValue("a")), obj(types.StringValue("a"))), true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.ListResponse{}
+			uniqueComponentKeysValidator{}.ValidateList(context.Background(), validator.ListRequest{ConfigValue: tc.list}, resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("HasError: got %v, want %v (%v)", resp.Diagnostics.HasError(), tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestCommentVisibilityValidator(t *testing.T) {
+	actionType := map[string]attr.Type{"type": types.StringType, "args": types.MapType{ElemType: types.StringType}}
+	objType := map[string]attr.Type{
+		"type": types.StringType,
+		"args": types.MapType{ElemType: types.StringType},
+		"then": types.ListType{ElemType: types.ObjectType{AttrTypes: actionType}},
+	}
+	args := func(kv ...string) types.Map {
+		elems := map[string]attr.Value{}
+		for i := 0; i < len(kv); i += 2 {
+			elems[kv[i]] = types.StringValue(kv[i+1])
+		}
+		return types.MapValueMust(types.StringType, elems)
+	}
+	thenNull := types.ListNull(types.ObjectType{AttrTypes: actionType})
+	component := func(typ string, a types.Map) attr.Value {
+		return types.ObjectValueMust(objType, map[string]attr.Value{"type": types.StringValue(typ), "args": a, "then": thenNull})
+	}
+	list := func(elems ...attr.Value) types.List {
+		return types.ListValueMust(types.ObjectType{AttrTypes: objType}, elems)
+	}
+
+	unknownValue := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"message": types.StringValue("hi"), "visibility_type": types.StringValue("ROLE"), "visibility_value": types.StringUnknown(),
+	})
+	nested := types.ObjectValueMust(objType, map[string]attr.Value{
+		"type": types.StringValue("condition"),
+		"args": args("first", "a", "operator", "equals", "second", "a"),
+		"then": types.ListValueMust(types.ObjectType{AttrTypes: actionType}, []attr.Value{
+			types.ObjectValueMust(actionType, map[string]attr.Value{"type": types.StringValue("comment"), "args": args("message", "hi", "visibility_type", "GROUP")}),
+		}),
+	})
+
+	cases := map[string]struct {
+		list     types.List
+		wantPath string
+	}{
+		"public":             {list(component("comment", args("message", "hi"))), ""},
+		"role":               {list(component("comment", args("message", "hi", "visibility_type", "ROLE", "visibility_value", "Developers"))), ""},
+		"type without value": {list(component("log", args("message", "x")), component("comment", args("message", "hi", "visibility_type", "ROLE"))), "components[1].args"},
+		"value without type": {list(component("comment", args("message", "hi", "visibility_value", "Developers"))), "components[0].args"},
+		"unknown type":       {list(component("comment", args("message", "hi", "visibility_type", "USER", "visibility_value", "x"))), "components[0].args"},
+		"unknown value":      {list(component("comment", unknownValue)), ""},
+		"other component":    {list(component("log", args("visibility_type", "ROLE"))), ""},
+		"nested in then":     {list(nested), "components[0].then[0].args"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &validator.ListResponse{}
+			commentVisibilityValidator{}.ValidateList(context.Background(), validator.ListRequest{Path: path.Root("components"), ConfigValue: tc.list}, resp)
+			if tc.wantPath == "" {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("unexpected error: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.ErrorsCount() != 1 {
+				t.Fatalf("expected one error, got %v", resp.Diagnostics)
+			}
+			d, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+			if !ok || d.Path().String() != tc.wantPath {
+				t.Errorf("error path: got %v, want %s", resp.Diagnostics[0], tc.wantPath)
+			}
+			if !strings.Contains(d.Detail(), "component ") {
+				t.Errorf("expected the component index in %q", d.Detail())
+			}
+		})
+	}
+}
+
+func TestProjectIDValidator(t *testing.T) {
+	schemaResp := &fwresource.SchemaResponse{}
+	NewRuleResource().Schema(context.Background(), fwresource.SchemaRequest{}, schemaResp)
+	validators := schemaResp.Schema.Attributes["project_id"].(schema.StringAttribute).Validators
+
+	for _, tc := range []struct {
+		value   string
+		wantErr bool
+	}{
+		{"10001", false},
+		{"OPS", true},
+		{"10001 ", true},
+	} {
+		resp := &validator.StringResponse{}
+		req := validator.StringRequest{Path: path.Root("project_id"), ConfigValue: types.StringValue(tc.value)}
+		for _, v := range validators {
+			v.ValidateString(context.Background(), req, resp)
+		}
+		if resp.Diagnostics.HasError() != tc.wantErr {
+			t.Errorf("project_id %q: HasError got %v, want %v", tc.value, resp.Diagnostics.HasError(), tc.wantErr)
+		}
+	}
+}
+
+func TestNonEmptyComponentsValidator(t *testing.T) {
+	ctx := context.Background()
+	empty := types.ListValueMust(types.StringType, []attr.Value{})
+	one := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("x")})
+
+	resp := &validator.ListResponse{}
+	nonEmptyComponentsValidator{}.ValidateList(ctx, validator.ListRequest{ConfigValue: empty}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected error for empty components list")
+	}
+
+	resp = &validator.ListResponse{}
+	nonEmptyComponentsValidator{}.ValidateList(ctx, validator.ListRequest{ConfigValue: one}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+}
+
+func TestStateFromEnabledModifier(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ruleResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	// The rule was disabled in the UI, so Read left DISABLED in state.
+	drifted := types.StringValue("DISABLED")
+	tests := []struct {
+		name    string
+		enabled types.Bool
+		want    types.String
+	}{
+		{"enabled in config", types.BoolValue(true), types.StringValue("ENABLED")},
+		{"disabled in config", types.BoolValue(false), types.StringValue("DISABLED")},
+		{"enabled not yet known", types.BoolUnknown(), types.StringUnknown()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			if d := plan.SetAttribute(ctx, path.Root("enabled"), tt.enabled); d.HasError() {
+				t.Fatalf("setting enabled: %v", d)
+			}
+			req := planmodifier.StringRequest{Plan: plan, StateValue: drifted, PlanValue: drifted}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			stateFromEnabledModifier{}.PlanModifyString(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if !resp.PlanValue.Equal(tt.want) {
+				t.Errorf("state: got %v, want %v", resp.PlanValue, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmartValueBracesValidator(t *testing.T) {
+	cases := map[string]struct {
+		value    string
+		wantWarn bool
+	}{
+		"plain text":       {"hello", false},
+		"balanced":         {"{{issue.key}} moved to {{issue.status.name}}", false},
+		"nested":           {"{{#if(issue.fixVersions)}}{{issue.fixVersions.name}}{{/}}", false},
+		"missing close":    {"{{issue.status.name}", true},
+		"missing open":     {"issue.status.name}}", true},
+		"single braces ok": {`{"a":1}`, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			args := types.MapValueMust(types.StringType, map[string]attr.Value{"message": types.StringValue(tc.value)})
+			resp := &validator.MapResponse{}
+			smartValueBracesValidator{}.ValidateMap(context.Background(), validator.MapRequest{Path: path.Root("args"), ConfigValue: args}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != tc.wantWarn {
+				t.Errorf("warning: got %v, want %v (%v)", got, tc.wantWarn, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestSyncManagedLabel_SkipsWhenTagged(t *testing.T) {
+	var tagged atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"accountId":"acct-1"}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/rule-labels"):
+			fmt.Fprint(w, `[{"id":7,"name":"managed-by:terraform"}]`)
+		case r.Method == http.MethodPut && strings.Contains(r.URL.Path, "/labels/7"):
+			tagged.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+	r := &ruleResource{client: c}
+
+	ctx := context.Background()
+	model := ruleResourceModel{
+		Scope:  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ari:cloud:jira:cloud-123:project/10001")}),
+		Labels: types.ListValueMust(types.StringType, []attr.Value{}),
+	}
+
+	var diags diag.Diagnostics
+	r.syncManagedLabel(ctx, "r1", &model, &diags)
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got := tagged.Load(); got != 1 {
+		t.Fatalf("AddLabelToRule calls: got %d, want 1", got)
+	}
+	if labels := toStringSlice(ctx, model.Labels); len(labels) != 1 || labels[0] != "managed-by:terraform" {
+		t.Fatalf("labels after tagging: got %v", labels)
+	}
+
+	// A second sync sees the label on the model and makes no request.
+	r.syncManagedLabel(ctx, "r1", &model, &diags)
+	if got := tagged.Load(); got != 1 {
+		t.Errorf("AddLabelToRule calls after second sync: got %d, want 1", got)
+	}
+}
+
+func TestSyncMetadataLabels(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path[strings.LastIndex(r.URL.Path, "/10001/")+len("/10001"):])
+		mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/rule-labels"):
+			fmt.Fprint(w, `[{"id":1,"name":"managed-by:terraform"},{"id":2,"name":"team:old"},{"id":3,"name":"env:prod"},{"id":4,"name":"other:x"}]`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rule-labels"):
+			fmt.Fprint(w, `{"id":9,"name":"team:payments"}`)
+		case r.Method == http.MethodPut || r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+	r := &ruleResource{client: c}
+
+	ctx := context.Background()
+	labels := func(names ...string) types.List {
+		elems := make([]attr.Value, len(names))
+		for i, name := range names {
+			elems[i] = types.StringValue(name)
+		}
+		return types.ListValueMust(types.StringType, elems)
+	}
+	model := ruleResourceModel{
+		Scope:  labels("ari:cloud:jira:cloud-123:project/10001"),
+		Labels: labels("managed-by:terraform", "team:old", "env:prod", "other:x"),
+	}
+	metadata := types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("payments")})
+	prior := types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("old"), "env": types.StringValue("prod")})
+
+	// team changes value and env is dropped; other:x isn't metadata this rule manages.
+	var diags diag.Diagnostics
+	r.syncMetadataLabels(ctx, "r1", metadata, prior, &model, &diags)
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	wantCalls := []string{
+		"GET /rule-labels",
+		"DELETE /rules/r1/labels/2",
+		"DELETE /rules/r1/labels/3",
+		"POST /rule-labels",
+		"PUT /rules/r1/labels/9",
+	}
+	if !slices.Equal(calls, wantCalls) {
+		t.Errorf("calls:\n got %v\nwant %v", calls, wantCalls)
+	}
+	if got, want := toStringSlice(ctx, model.Labels), []string{"managed-by:terraform", "other:x", "team:payments"}; !slices.Equal(got, want) {
+		t.Errorf("labels: got %v, want %v", got, want)
+	}
+	if !model.Metadata.Equal(metadata) {
+		t.Errorf("metadata: got %v, want %v", model.Metadata, metadata)
+	}
+
+	// Metadata on a rule without a project can't be stored, so it's left out
+	// of state with a warning.
+	calls = nil
+	model = ruleResourceModel{Scope: types.ListNull(types.StringType), Labels: types.ListNull(types.StringType)}
+	diags = nil
+	r.syncMetadataLabels(ctx, "r1", metadata, types.MapNull(types.StringType), &model, &diags)
+	if diags.WarningsCount() != 1 || len(calls) != 0 {
+		t.Errorf("global rule: got diagnostics %v and calls %v, want one warning and no calls", diags, calls)
+	}
+	if got := model.Metadata.Elements(); len(got) != 0 {
+		t.Errorf("global rule metadata: got %v, want empty", got)
+	}
+}
+
+func TestMetadataFromLabels(t *testing.T) {
+	ctx := context.Background()
+	labels := []string{"managed-by:terraform", "team:old", "team:payments", "env:prod", "plain"}
+
+	if got := metadataFromLabels(ctx, types.MapNull(types.StringType), labels, "managed-by:terraform"); !got.IsNull() {
+		t.Errorf("unconfigured: got %v, want null", got)
+	}
+
+	configured := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team":       types.StringValue("payments"),
+		"managed-by": types.StringValue("me"),
+		"owner":      types.StringValue("alice"),
+	})
+	got := toStringMap(ctx, metadataFromLabels(ctx, configured, labels, "managed-by:terraform"))
+	want := map[string]string{"team": "payments"}
+	if !maps.Equal(got, want) {
+		t.Errorf("configured: got %v, want %v", got, want)
+	}
+}
+
+func TestGeneratedPayload_RedactsSecureHeaders(t *testing.T) {
+	trigger := json.RawMessage(`{"component":"TRIGGER","type":"jira.manual.trigger.issue"}`)
+	webhook, err := buildAddReleaseRelatedWork(map[string]string{
+		"version_field": "customfield_10709",
+		"category":      "other",
+		"title":         "t",
+		"url":           "https://example.com",
+	}, "cloud-123", "user@test.com", "secret-token")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	condition := json.RawMessage(`{"component":"CONDITION","type":"jira.condition.container.block","children":[` + string(webhook) + `]}`)
+
+	gotTrigger, gotComponents := generatedPayload(trigger, []json.RawMessage{condition})
+
+	if !strings.Contains(gotTrigger.ValueString(), "\n  \"type\": \"jira.manual.trigger.issue\"") {
+		t.Errorf("trigger not indented: %s", gotTrigger.ValueString())
+	}
+	auth := relatedWorkAuthHeader("user@test.com", "secret-token")
+	if strings.Contains(gotComponents.ValueString(), auth) {
+		t.Errorf("secure header value leaked: %s", gotComponents.ValueString())
+	}
+	if !strings.Contains(gotComponents.ValueString(), redactedValue) {
+		t.Errorf("expected %q in output: %s", redactedValue, gotComponents.ValueString())
+	}
+}
+
+func TestProjectIDRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ruleResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	stateWithScope := func(scope string) tfsdk.State {
+		state := tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		}
+		diags := state.SetAttribute(ctx, path.Root("scope"), []string{scope})
+		if diags.HasError() {
+			t.Fatalf("building state: %v", diags)
+		}
+		return state
+	}
+
+	tests := []struct {
+		name string
+		plan types.String
+		want bool
+	}{
+		{"imported rule gains matching project_id", types.StringValue("10001"), false},
+		{"moved to another project", types.StringValue("10002"), true},
+		{"project_id removed", types.StringNull(), true},
+		{"unknown project_id", types.StringUnknown(), true},
+	}
+	nullPlan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.StringRequest{State: stateWithScope("ari:cloud:jira:c:project/10001"), Plan: nullPlan, PlanValue: tt.plan}
+			resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+			projectIDRequiresReplace(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tt.want {
+				t.Errorf("RequiresReplace: got %v, want %v", resp.RequiresReplace, tt.want)
+			}
+		})
+	}
+
+	// Swapping project_id for scope_aris is up to scope_aris.
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: nullPlan.Raw}
+	if diags := plan.SetAttribute(ctx, path.Root("scope_aris"), []string{"ari:cloud:jira:c:project/10001"}); diags.HasError() {
+		t.Fatalf("building plan: %v", diags)
+	}
+	req := planmodifier.StringRequest{State: stateWithScope("ari:cloud:jira:c:project/10001"), Plan: plan, PlanValue: types.StringNull()}
+	resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+	projectIDRequiresReplace(ctx, req, resp)
+	if resp.Diagnostics.HasError() || resp.RequiresReplace {
+		t.Errorf("project_id replaced by scope_aris: RequiresReplace %v, diagnostics %v", resp.RequiresReplace, resp.Diagnostics)
+	}
+}
+
+func TestScopeARIsRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ruleResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	current := []string{"ari:cloud:jira:c:project/10001", "ari:cloud:jira-servicedesk:c:queue/7"}
+	if diags := state.SetAttribute(ctx, path.Root("scope"), current); diags.HasError() {
+		t.Fatalf("building state: %v", diags)
+	}
+	list := func(aris ...string) types.List {
+		v, _ := types.ListValueFrom(ctx, types.StringType, aris)
+		return v
+	}
+
+	tests := []struct {
+		name string
+		plan types.List
+		want bool
+	}{
+		{"matches scope in another order", list(current[1], current[0]), false},
+		{"different scope", list(current[0]), true},
+		{"removed", types.ListNull(types.StringType), false},
+		{"unknown", types.ListUnknown(types.StringType), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.ListRequest{State: state, PlanValue: tt.plan}
+			resp := &listplanmodifier.RequiresReplaceIfFuncResponse{}
+			scopeARIsRequiresReplace(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tt.want {
+				t.Errorf("RequiresReplace: got %v, want %v", resp.RequiresReplace, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriggerWebhookURL(t *testing.T) {
+	// The documented incoming-webhook value: webhookToken, searchOrProvide, jql.
+	got := triggerWebhookURL(json.RawMessage(`{"component":"TRIGGER","type":"jira.incoming.webhook","schemaVersion":1,"value":{"webhookToken":"abc","searchOrProvide":"provided","jql":null}}`))
+	if got.ValueString() != "https://automation.atlassian.com/pro/hooks/abc" {
+		t.Errorf("webhook trigger: got %v", got)
+	}
+	if got := triggerWebhookURL(json.RawMessage(`{"type":"jira.incoming.webhook","value":{}}`)); !got.IsNull() {
+		t.Errorf("webhook trigger without a token: got %v, want null", got)
+	}
+	if got := triggerWebhookURL(json.RawMessage(`{"type":"jira.issue.event.trigger:transitioned","value":{}}`)); !got.IsNull() {
+		t.Errorf("non-webhook trigger: got %v, want null", got)
+	}
+}
+
+func TestRuleTimeouts(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	NewRuleResource().Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	null := nullTimeouts(ctx)
+	if want := schemaResp.Schema.Blocks["timeouts"].Type(); !null.Type(ctx).Equal(want) {
+		t.Fatalf("nullTimeouts type %s doesn't match the schema's %s", null.Type(ctx), want)
+	}
+	if got, diags := null.Read(ctx, defaultOperationTimeout); diags.HasError() || got != defaultOperationTimeout {
+		t.Errorf("no block: got %s, %v; want the default", got, diags)
+	}
+
+	attrTypes := null.AttributeTypes(ctx)
+	attrs := map[string]attr.Value{}
+	for name := range attrTypes {
+		attrs[name] = types.StringNull()
+	}
+	attrs["create"] = types.StringValue("90s")
+	set := timeouts.Value{Object: types.ObjectValueMust(attrTypes, attrs)}
+	if got, _ := set.Create(ctx, defaultOperationTimeout); got != 90*time.Second {
+		t.Errorf("create: got %s, want 90s", got)
+	}
+	if got, _ := set.Delete(ctx, defaultOperationTimeout); got != defaultOperationTimeout {
+		t.Errorf("unset delete: got %s, want the default", got)
+	}
+}
+
+func TestWebhookURLModifier(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	(&ruleResource{}).Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	withTriggerJSON := func(triggerJSON string) tftypes.Value {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objType, nil)}
+		if d := state.SetAttribute(ctx, path.Root("trigger_json"), triggerJSON); d.HasError() {
+			t.Fatalf("building value: %v", d)
+		}
+		return state.Raw
+	}
+	prior := types.StringValue("https://automation.atlassian.com/pro/hooks/abc")
+
+	tests := []struct {
+		name     string
+		planned  string
+		wantKept bool
+	}{
+		{"trigger unchanged", `{"type":"jira.incoming.webhook"}`, true},
+		{"trigger changed", `{"type":"jira.manual.trigger.issue"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				State:      tfsdk.State{Schema: schemaResp.Schema, Raw: withTriggerJSON(`{"type":"jira.incoming.webhook"}`)},
+				Plan:       tfsdk.Plan{Schema: schemaResp.Schema, Raw: withTriggerJSON(tt.planned)},
+				StateValue: prior,
+				PlanValue:  types.StringUnknown(),
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			webhookURLModifier{}.PlanModifyString(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if kept := resp.PlanValue.Equal(prior); kept != tt.wantKept {
+				t.Errorf("kept prior: got %v, want %v (plan %v)", kept, tt.wantKept, resp.PlanValue)
+			}
+		})
+	}
+}
+
+func TestSavePartialState_ReadFails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"accountId":"acct-1"}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+	r := &ruleResource{client: c}
+
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	resp := &fwresource.CreateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+
+	plan := ruleResourceModel{
+		ID:                      types.StringUnknown(),
+		Name:                    types.StringValue("rule"),
+		Enabled:                 types.BoolValue(true),
+		State:                   types.StringUnknown(),
+		Scope:                   types.ListUnknown(types.StringType),
+		Labels:                  types.ListUnknown(types.StringType),
+		ScopeARIs:               types.ListNull(types.StringType),
+		Metadata:                types.MapNull(types.StringType),
+		AuthorID:                types.StringUnknown(),
+		WebhookURL:              types.StringUnknown(),
+		SystemOwned:             types.BoolUnknown(),
+		AllowSystem:             types.BoolValue(false),
+		TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+		ComponentsJSON:          jsontypes.NewNormalizedValue(`[]`),
+		GeneratedTriggerJSON:    types.StringValue("{}"),
+		GeneratedComponentsJSON: types.StringValue("[]"),
+		Timeouts:                nullTimeouts(context.Background()),
+	}
+	r.savePartialState(ctx, "r1", &plan, resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+	var saved ruleResourceModel
+	if d := resp.State.Get(ctx, &saved); d.HasError() {
+		t.Fatalf("reading saved state: %v", d)
+	}
+	if saved.ID.ValueString() != "r1" || saved.Name.ValueString() != "rule" {
+		t.Errorf("saved id/name: got %v/%v", saved.ID, saved.Name)
+	}
+	if !saved.State.IsNull() || !saved.Scope.IsNull() {
+		t.Errorf("unread computed values should be null, got state %v scope %v", saved.State, saved.Scope)
+	}
+}
+
+func TestCreate_StateInPayload(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		apiIgnores   bool // API creates the rule DISABLED regardless of the payload.
+		wantSetState int32
+	}{
+		{name: "enabled", enabled: true},
+		{name: "disabled", enabled: false},
+		{name: "enabled, API ignores payload state", enabled: true, apiIgnores: true, wantSetState: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var setState atomic.Int32
+			var ruleState atomic.Value
+			mux := http.NewServeMux()
+			mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+			})
+			mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"accountId":"acct-1"}`)
+			})
+			mux.HandleFunc("POST /api/rule", func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Rule struct {
+						State string `json:"state"`
+					} `json:"rule"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				if tt.apiIgnores {
+					body.Rule.State = "DISABLED"
+				}
+				ruleState.Store(body.Rule.State)
+				fmt.Fprint(w, `{"uuid":"r1"}`)
+			})
+			mux.HandleFunc("PUT /api/rule/r1/state", func(w http.ResponseWriter, r *http.Request) {
+				setState.Add(1)
+				var body struct {
+					Value string `json:"value"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				ruleState.Store(body.Value)
+				w.WriteHeader(http.StatusNoContent)
+			})
+			mux.HandleFunc("GET /api/rule/r1", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprintf(w, `{"rule":{"uuid":"r1","name":"rule","state":%q,"trigger":{"component":"TRIGGER","type":"t"},"components":[{"component":"ACTION","type":"codebarrel.action.log","value":"hi"}]}}`, ruleState.Load())
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			c.BaseURL = srv.URL + "/api"
+			c.ManageLabel = false
+			r := &ruleResource{client: c}
+
+			ctx := context.Background()
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			plan := ruleResourceModel{
+				ID:                      types.StringUnknown(),
+				Name:                    types.StringValue("rule"),
+				Enabled:                 types.BoolValue(tt.enabled),
+				State:                   types.StringUnknown(),
+				Scope:                   types.ListUnknown(types.StringType),
+				Labels:                  types.ListUnknown(types.StringType),
+				ScopeARIs:               types.ListNull(types.StringType),
+				Metadata:                types.MapNull(types.StringType),
+				AuthorID:                types.StringUnknown(),
+				WebhookURL:              types.StringUnknown(),
+				SystemOwned:             types.BoolUnknown(),
+				AllowSystem:             types.BoolValue(false),
+				TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+				ComponentsJSON:          jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"codebarrel.action.log","value":"hi"}]`),
+				GeneratedTriggerJSON:    types.StringUnknown(),
+				GeneratedComponentsJSON: types.StringUnknown(),
+				Timeouts:                nullTimeouts(context.Background()),
+			}
+			req := fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema}}
+			if d := req.Plan.Set(ctx, &plan); d.HasError() {
+				t.Fatalf("setting plan: %v", d)
+			}
+			resp := &fwresource.CreateResponse{State: tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}}
+
+			r.Create(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if got := setState.Load(); got != tt.wantSetState {
+				t.Errorf("SetRuleState calls: got %d, want %d", got, tt.wantSetState)
+			}
+			var saved ruleResourceModel
+			resp.State.Get(ctx, &saved)
+			if saved.Enabled.ValueBool() != tt.enabled {
+				t.Errorf("enabled in state: got %v, want %v", saved.Enabled, tt.enabled)
+			}
+		})
+	}
+}
+
+func TestCreate_ReadOnly(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected write in read-only mode: %s %s", r.Method, r.URL.Path)
+		}
+		http.NotFound(w, r)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	c.BaseURL = srv.URL + "/api"
+	c.ReadOnly = true
+	r := &ruleResource{client: c}
+
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	plan := ruleResourceModel{
+		ID:                      types.StringUnknown(),
+		Name:                    types.StringValue("rule"),
+		Enabled:                 types.BoolValue(true),
+		State:                   types.StringUnknown(),
+		Scope:                   types.ListUnknown(types.StringType),
+		Labels:                  types.ListUnknown(types.StringType),
+		ScopeARIs:               types.ListNull(types.StringType),
+		Metadata:                types.MapNull(types.StringType),
+		AuthorID:                types.StringUnknown(),
+		WebhookURL:              types.StringUnknown(),
+		SystemOwned:             types.BoolUnknown(),
+		AllowSystem:             types.BoolValue(false),
+		TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+		ComponentsJSON:          jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"codebarrel.action.log","value":"hi"}]`),
+		GeneratedTriggerJSON:    types.StringUnknown(),
+		GeneratedComponentsJSON: types.StringUnknown(),
+		Timeouts:                nullTimeouts(context.Background()),
+	}
+	req := fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema}}
+	if d := req.Plan.Set(ctx, &plan); d.HasError() {
+		t.Fatalf("setting plan: %v", d)
+	}
+	resp := &fwresource.CreateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+
+	r.Create(ctx, req, resp)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Provider is read-only" {
+		t.Fatalf("got diagnostics %v, want a read-only error", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("state was set for a rule that wasn't created")
+	}
+}
+
+func TestValidateConfig_MixedForms(t *testing.T) {
+	ctx := context.Background()
+	r := &ruleResource{}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	structuredTrigger := &triggerModel{
+		Type: types.StringValue("manual"),
+		Args: types.MapNull(types.StringType),
+		When: types.MapNull(types.StringType),
+	}
+	rawTrigger := jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"jira.manual.trigger.issue"}`)
+	rawComponents := jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"codebarrel.action.log"}]`)
+
+	tests := []struct {
+		name    string
+		set     map[string]interface{}
+		warning bool
+	}{
+		{"structured trigger with components_json", map[string]interface{}{"trigger": structuredTrigger, "components_json": rawComponents}, true},
+		{"all JSON", map[string]interface{}{"trigger_json": rawTrigger, "components_json": rawComponents}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			for attr, v := range tt.set {
+				if d := config.SetAttribute(ctx, path.Root(attr), v); d.HasError() {
+					t.Fatalf("building config: %v", d)
+				}
+			}
+			req := fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}
+			resp := &fwresource.ValidateConfigResponse{}
+
+			r.ValidateConfig(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != tt.warning {
+				t.Errorf("warning: got %v, want %v (%v)", got, tt.warning, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestUpdate_SingleRead(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		webhookURL   types.String // As planned: unknown when the trigger changed.
+		wantGets     int32
+		wantSetState int32
+	}{
+		{name: "unchanged state and trigger", enabled: true, webhookURL: types.StringNull(), wantGets: 1},
+		{name: "disabling", enabled: false, webhookURL: types.StringNull(), wantGets: 1, wantSetState: 1},
+		{name: "changed trigger is read back", enabled: true, webhookURL: types.StringUnknown(), wantGets: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gets, setState atomic.Int32
+			var name atomic.Value
+			name.Store("old")
+			mux := http.NewServeMux()
+			mux.HandleFunc("/_edge/tenant_info", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"cloudId":"cloud-123"}`)
+			})
+			mux.HandleFunc("/rest/api/3/myself", func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"accountId":"acct-1"}`)
+			})
+			mux.HandleFunc("GET /api/rule/r1", func(w http.ResponseWriter, _ *http.Request) {
+				gets.Add(1)
+				fmt.Fprintf(w, `{"rule":{"uuid":"r1","name":%q,"state":"ENABLED","authorAccountId":"acct-1","trigger":{"id":"1","component":"TRIGGER","type":"t"},"components":[]}}`, name.Load())
+			})
+			mux.HandleFunc("PUT /api/rule/r1", func(w http.ResponseWriter, _ *http.Request) {
+				name.Store("new")
+				w.WriteHeader(http.StatusNoContent)
+			})
+			mux.HandleFunc("PUT /api/rule/r1/state", func(w http.ResponseWriter, _ *http.Request) {
+				setState.Add(1)
+				w.WriteHeader(http.StatusNoContent)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			c, err := client.New(srv.URL, "user@test.com", "token", "", "", nil)
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			c.BaseURL = srv.URL + "/api"
+			c.ManageLabel = false
+			r := &ruleResource{client: c}
+
+			ctx := context.Background()
+			schemaResp := &fwresource.SchemaResponse{}
+			r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+			prior := ruleResourceModel{
+				ID:                      types.StringValue("r1"),
+				Name:                    types.StringValue("old"),
+				Description:             types.StringValue(""),
+				Enabled:                 types.BoolValue(true),
+				State:                   types.StringValue("ENABLED"),
+				Scope:                   types.ListNull(types.StringType),
+				Labels:                  types.ListNull(types.StringType),
+				ScopeARIs:               types.ListNull(types.StringType),
+				Metadata:                types.MapNull(types.StringType),
+				AuthorID:                types.StringValue("acct-1"),
+				WebhookURL:              types.StringNull(),
+				SystemOwned:             types.BoolValue(false),
+				AllowSystem:             types.BoolValue(false),
+				Recreate:                types.BoolValue(true),
+				TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+				ComponentsJSON:          jsontypes.NewNormalizedValue(`[{"component":"ACTION","type":"codebarrel.action.log","value":"hi"}]`),
+				GeneratedTriggerJSON:    types.StringNull(),
+				GeneratedComponentsJSON: types.StringNull(),
+				Timeouts:                nullTimeouts(context.Background()),
+			}
+			plan := prior
+			plan.Name = types.StringValue("new")
+			plan.Enabled = types.BoolValue(tt.enabled)
+			plan.WebhookURL = tt.webhookURL
+			req := fwresource.UpdateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema}, State: tfsdk.State{Schema: schemaResp.Schema}}
+			if d := req.Plan.Set(ctx, &plan); d.HasError() {
+				t.Fatalf("setting plan: %v", d)
+			}
+			if d := req.State.Set(ctx, &prior); d.HasError() {
+				t.Fatalf("setting state: %v", d)
+			}
+			resp := &fwresource.UpdateResponse{State: req.State}
+
+			r.Update(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if got := gets.Load(); got != tt.wantGets {
+				t.Errorf("rule reads: got %d, want %d", got, tt.wantGets)
+			}
+			if got := setState.Load(); got != tt.wantSetState {
+				t.Errorf("SetRuleState calls: got %d, want %d", got, tt.wantSetState)
+			}
+			var saved ruleResourceModel
+			resp.State.Get(ctx, &saved)
+			if saved.Name.ValueString() != "new" || saved.Enabled.ValueBool() != tt.enabled {
+				t.Errorf("state: got name %v enabled %v, want new %v", saved.Name, saved.Enabled, tt.enabled)
+			}
+		})
+	}
+}
+
+func TestModifyPlan_DefaultEnabled(t *testing.T) {
+	ctx := context.Background()
+	r := &ruleResource{client: &client.Client{DefaultEnabled: false, DebugLogPrefix: client.DefaultDebugLogPrefix}}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	tests := []struct {
+		name       string
+		configured types.Bool
+		want       bool
+	}{
+		{name: "unset follows provider default", configured: types.BoolNull(), want: false},
+		{name: "explicit true wins", configured: types.BoolValue(true), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := ruleResourceModel{
+				ID:                      types.StringUnknown(),
+				Name:                    types.StringValue("rule"),
+				Enabled:                 tt.configured,
+				State:                   types.StringUnknown(),
+				Scope:                   types.ListUnknown(types.StringType),
+				Labels:                  types.ListUnknown(types.StringType),
+				ScopeARIs:               types.ListNull(types.StringType),
+				Metadata:                types.MapNull(types.StringType),
+				AuthorID:                types.StringUnknown(),
+				WebhookURL:              types.StringUnknown(),
+				SystemOwned:             types.BoolUnknown(),
+				AllowSystem:             types.BoolValue(false),
+				TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+				ComponentsJSON:          jsontypes.NewNormalizedValue(`[]`),
+				GeneratedTriggerJSON:    types.StringUnknown(),
+				GeneratedComponentsJSON: types.StringUnknown(),
+				Timeouts:                nullTimeouts(context.Background()),
+			}
+			config := tfsdk.State{Schema: schemaResp.Schema}
+			if d := config.Set(ctx, &model); d.HasError() {
+				t.Fatalf("building config: %v", d)
+			}
+			// Optional+computed attributes the config leaves null are unknown in the plan.
+			if model.Enabled.IsNull() {
+				model.Enabled = types.BoolUnknown()
+			}
+			plan := tfsdk.Plan{Schema: schemaResp.Schema}
+			if d := plan.Set(ctx, &model); d.HasError() {
+				t.Fatalf("building plan: %v", d)
+			}
+			req := fwresource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw},
+				Plan:   plan,
+				State: tfsdk.State{
+					Schema: schemaResp.Schema,
+					Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+				},
+			}
+			resp := &fwresource.ModifyPlanResponse{Plan: plan}
+
+			r.ModifyPlan(ctx, req, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			var got ruleResourceModel
+			resp.Plan.Get(ctx, &got)
+			if got.Enabled.IsUnknown() || got.Enabled.ValueBool() != tt.want {
+				t.Errorf("enabled: got %v, want %v", got.Enabled, tt.want)
+			}
+		})
+	}
+}
+
+func TestModifyPlan_DefaultEnabledUpdate(t *testing.T) {
+	ctx := context.Background()
+	r := &ruleResource{client: &client.Client{DefaultEnabled: true, DebugLogPrefix: client.DefaultDebugLogPrefix}}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	// The rule was created disabled and enabled was later removed from the config.
+	prior := ruleResourceModel{
+		ID:                      types.StringValue("rule-uuid"),
+		Name:                    types.StringValue("rule"),
+		Enabled:                 types.BoolValue(false),
+		State:                   types.StringValue("DISABLED"),
+		Scope:                   types.ListNull(types.StringType),
+		Labels:                  types.ListNull(types.StringType),
+		ScopeARIs:               types.ListNull(types.StringType),
+		Metadata:                types.MapNull(types.StringType),
+		AuthorID:                types.StringValue("acct-1"),
+		WebhookURL:              types.StringNull(),
+		SystemOwned:             types.BoolValue(false),
+		AllowSystem:             types.BoolValue(false),
+		TriggerJSON:             jsontypes.NewNormalizedValue(`{"component":"TRIGGER","type":"t"}`),
+		ComponentsJSON:          jsontypes.NewNormalizedValue(`[]`),
+		GeneratedTriggerJSON:    types.StringNull(),
+		GeneratedComponentsJSON: types.StringNull(),
+		Timeouts:                nullTimeouts(context.Background()),
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if d := state.Set(ctx, &prior); d.HasError() {
+		t.Fatalf("building state: %v", d)
+	}
+	configModel := prior
+	configModel.Enabled = types.BoolNull()
+	config := tfsdk.State{Schema: schemaResp.Schema}
+	if d := config.Set(ctx, &configModel); d.HasError() {
+		t.Fatalf("building config: %v", d)
+	}
+	// The state attribute's plan modifier has already planned from the prior enabled.
+	plan := tfsdk.Plan{Schema: schemaResp.Schema}
+	if d := plan.Set(ctx, &prior); d.HasError() {
+		t.Fatalf("building plan: %v", d)
+	}
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw},
+		Plan:   plan,
+		State:  state,
+	}
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+
+	r.ModifyPlan(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	var got ruleResourceModel
+	resp.Plan.Get(ctx, &got)
+	if !got.Enabled.ValueBool() {
+		t.Errorf("enabled: got %v, want true", got.Enabled)
+	}
+	if got.State.ValueString() != "ENABLED" {
+		t.Errorf("state: got %v, want ENABLED to match enabled", got.State)
+	}
+}
+
+func TestPlanActor(t *testing.T) {
+	model := func(actorType, account types.String) *ruleResourceModel {
+		return &ruleResourceModel{ActorType: actorType, ActorAccountID: account}
+	}
+	current := model(types.StringValue("ACCOUNT_ID"), types.StringValue("acct-1"))
+
+	tests := []struct {
+		name  string
+		plan  *ruleResourceModel
+		state *ruleResourceModel
+		want  *client.RuleActor
+	}{
+		{"create unset", model(types.StringUnknown(), types.StringUnknown()), nil, nil},
+		{"create event initiator", model(types.StringValue("EVENT_INITIATOR"), types.StringNull()), nil,
+			&client.RuleActor{Type: "EVENT_INITIATOR"}},
+		{"create account defaults to client's", model(types.StringValue("ACCOUNT_ID"), types.StringUnknown()), nil,
+			&client.RuleActor{Type: "ACCOUNT_ID"}},
+		{"update unchanged", model(types.StringValue("ACCOUNT_ID"), types.StringValue("acct-1")), current, nil},
+		{"update other account", model(types.StringValue("ACCOUNT_ID"), types.StringValue("acct-2")), current,
+			&client.RuleActor{Type: "ACCOUNT_ID", Actor: "acct-2"}},
+		{"update to event initiator", model(types.StringValue("EVENT_INITIATOR"), types.StringNull()), current,
+			&client.RuleActor{Type: "EVENT_INITIATOR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := planActor(tt.plan, tt.state)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadKnown(t *testing.T) {
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":            tftypes.String,
+		"project_id":      tftypes.String,
+		"trigger":         tftypes.String,
+		"trigger_json":    tftypes.String,
+		"components":      tftypes.String,
+		"components_json": tftypes.String,
+	}}
+	plan := func(componentsJSON, name tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"name":            name,
+			"project_id":      tftypes.NewValue(tftypes.String, "10001"),
+			"trigger":         tftypes.NewValue(tftypes.String, nil),
+			"trigger_json":    tftypes.NewValue(tftypes.String, "{}"),
+			"components":      tftypes.NewValue(tftypes.String, nil),
+			"components_json": componentsJSON,
+		})
+	}
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+
+	if !payloadKnown(plan(tftypes.NewValue(tftypes.String, "[]"), unknown)) {
+		t.Error("unknown name should not affect the payload")
+	}
+	if payloadKnown(plan(unknown, tftypes.NewValue(tftypes.String, "r"))) {
+		t.Error("unknown components_json should make the payload unknown")
+	}
+}
+
 // --- HCL config templates ---
 
 func testAccRuleResourceConfig_basic(name string) string {
+	return testAccRuleResourceConfig_project(name, os.Getenv("JIRA_TEST_PROJECT_ID"))
+}
+
+func testAccRuleResourceConfig_project(name, projectID string) string {
 	return fmt.Sprintf(`
 resource "jira-automation_rule" "test" {
   name       = %[1]q
@@ -296,7 +1843,7 @@ resource "jira-automation_rule" "test" {
     }
   }]
 }
-`, name, os.Getenv("JIRA_TEST_PROJECT_ID"))
+`, name, projectID)
 }
 
 func testAccRuleResourceConfig_comment(name string) string {
@@ -440,6 +1987,85 @@ resource "jira-automation_rule" "test" {
 `, name, os.Getenv("JIRA_TEST_PROJECT_ID"))
 }
 
+func testAccRuleResourceConfig_conditionDebugWebhook(name string) string {
+	return fmt.Sprintf(`
+resource "jira-automation_rule" "test" {
+  name       = %[1]q
+  project_id = %[2]q
+
+  trigger = {
+    type = "status_transition"
+    args = {
+      from_status = "To Do"
+      to_status   = "In Progress"
+    }
+  }
+
+  components = [{
+    type = "condition"
+    args = {
+      first    = "{{issue.status.name}}"
+      operator = "equals"
+      second   = "In Progress"
+    }
+
+    then = [{
+      type = "add_release_related_work"
+      args = {
+        version_field = "customfield_10020"
+        category      = "other"
+        title         = "tf-acc-test: %[1]s"
+        url           = "https://example.com/tf-acc-test"
+        debug         = "true"
+      }
+    }]
+
+    else = [{
+      type = "log"
+      args = {
+        message = "tf-acc-test: condition was false"
+      }
+    }]
+  }]
+}
+`, name, os.Getenv("JIRA_TEST_PROJECT_ID"))
+}
+
+func testAccRuleResourceConfig_conditionEmptyElse(name string) string {
+	return fmt.Sprintf(`
+resource "jira-automation_rule" "test" {
+  name       = %[1]q
+  project_id = %[2]q
+
+  trigger = {
+    type = "status_transition"
+    args = {
+      from_status = "To Do"
+      to_status   = "In Progress"
+    }
+  }
+
+  components = [{
+    type = "condition"
+    args = {
+      first    = "{{issue.status.name}}"
+      operator = "equals"
+      second   = "In Progress"
+    }
+
+    then = [{
+      type = "log"
+      args = {
+        message = "tf-acc-test: condition was true"
+      }
+    }]
+
+    else = []
+  }]
+}
+`, name, os.Getenv("JIRA_TEST_PROJECT_ID"))
+}
+
 func testAccRuleResourceConfig_aliases(name string) string {
 	return fmt.Sprintf(`
 provider "jira-automation" {
@@ -465,6 +2091,11 @@ resource "jira-automation_rule" "test" {
     args = {
       message = "tf-acc-test: alias={{issue.my_status}}"
     }
+  }, {
+    type = "log"
+    args = {
+      message = "tf-acc-test: id={{issue.status}}"
+    }
   }]
 }
 `, name, os.Getenv("JIRA_TEST_PROJECT_ID"))
@@ -501,3 +2132,85 @@ resource "jira-automation_rule" "test" {
 }
 `, name, os.Getenv("JIRA_TEST_PROJECT_ID"), debugArg)
 }
+
+func TestParseComponentsJSON_StripsAPIIDs(t *testing.T) {
+	// Pasted from GET /rule: ids, parent ids and empty containers included.
+	pasted := `[{"id":"1","component":"CONDITION","type":"jira.condition.container.block","parentId":null,"conditionParentId":null,"children":[],"conditions":[
+		{"id":"2","component":"CONDITION_BLOCK","type":"jira.condition.if.block","conditionParentId":"1","children":[
+			{"id":"3","component":"ACTION","type":"codebarrel.action.log","parentId":"2","value":"hi","children":[],"conditions":[]}],"conditions":[]}]}]`
+	clean := `[{"component":"CONDITION","type":"jira.condition.container.block","children":[],"conditions":[
+		{"component":"CONDITION_BLOCK","type":"jira.condition.if.block","children":[
+			{"component":"ACTION","type":"codebarrel.action.log","value":"hi","children":[],"conditions":[]}],"conditions":[]}]}]`
+
+	got, err := parseComponentsJSON(pasted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, err := parseComponentsJSON(clean)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || string(got[0]) != string(want[0]) {
+		t.Errorf("pasted API JSON sends\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestResolveTriggerJSON_KeepsEnrichment(t *testing.T) {
+	r := &ruleResource{client: &client.Client{}}
+	model := &ruleResourceModel{TriggerJSON: jsontypes.NewNormalizedValue(`{"id":"9","component":"TRIGGER","type":"jira.issue.event.trigger:transitioned",` +
+		`"value":{"eventFilters":["ari:cloud:jira:c1:project/10001"],"eventKey":"jira:issue_updated","issueEvent":"issue_generic"}}`)}
+
+	got, diags := r.resolveTriggerJSON(context.Background(), model)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	want := `{"component":"TRIGGER","type":"jira.issue.event.trigger:transitioned",` +
+		`"value":{"eventFilters":["ari:cloud:jira:c1:project/10001"],"eventKey":"jira:issue_updated","issueEvent":"issue_generic"}}`
+	if string(got) != want {
+		t.Errorf("sent %s, want %s", got, want)
+	}
+}
+
+func TestRawJSONAliases(t *testing.T) {
+	aliases := map[string]string{"release_version": "customfield_10709"}
+	c := &client.Client{
+		FieldAliases:   aliases,
+		ReverseAliases: map[string]string{"customfield_10709": "release_version"},
+	}
+	r := &ruleResource{client: c}
+	cfg := json.RawMessage(`{"component":"ACTION","type":"codebarrel.action.log","value":"{{issue.release_version}}"}`)
+
+	// Disabled: raw JSON passes through verbatim in both directions.
+	if got, err := r.resolveRawJSON(cfg); err != nil || string(got) != string(cfg) {
+		t.Errorf("disabled resolve: got %s, %v", got, err)
+	}
+
+	c.AliasRawJSON = true
+	sent, err := r.resolveRawJSON(cfg)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !strings.Contains(string(sent), "{{issue.customfield_10709}}") {
+		t.Fatalf("resolve: got %s", sent)
+	}
+
+	apiNorm, err := normalizeRawJSON(sent)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	aliased, normalize, err := r.aliasRawJSON(apiNorm, normalizeRawJSON)
+	if err != nil {
+		t.Fatalf("aliasRawJSON: %v", err)
+	}
+	if !strings.Contains(aliased, "{{issue.release_version}}") {
+		t.Errorf("read back: got %s", aliased)
+	}
+
+	// Configs written with either the alias or the field ID are kept as-is.
+	for _, prior := range []string{string(cfg), string(sent)} {
+		kept := keepEquivalentJSON(jsontypes.NewNormalizedValue(prior), aliased, normalize, false)
+		if kept.ValueString() != prior {
+			t.Errorf("prior %s: got %s", prior, kept.ValueString())
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &ruleStateResource{}
+	_ resource.ResourceWithImportState = &ruleStateResource{}
+)
+
+// ruleStateResource manages only the enabled/disabled state of a rule whose
+// trigger and components are managed elsewhere (or by hand).
+type ruleStateResource struct {
+	client *client.Client
+}
+
+type ruleStateResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	UUID             types.String `tfsdk:"uuid"`
+	Enabled          types.Bool   `tfsdk:"enabled"`
+	State            types.String `tfsdk:"state"`
+	DisableOnDestroy types.Bool   `tfsdk:"disable_on_destroy"`
+}
+
+func NewRuleStateResource() resource.Resource {
+	return &ruleStateResource{}
+}
+
+func (r *ruleStateResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rule_state"
+}
+
+func (r *ruleStateResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages only the enabled/disabled state of an existing Jira Automation rule. The rule's trigger and components are never touched.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Rule UUID.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uuid": schema.StringAttribute{
+				Required:    true,
+				Description: "UUID of the rule whose state is managed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether the rule is enabled.",
+			},
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "Rule state (ENABLED or DISABLED).",
+				PlanModifiers: []planmodifier.String{
+					stateFromEnabledModifier{},
+				},
+			},
+			"disable_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Disable the rule on destroy. Defaults to false, which leaves the rule in its current state.",
+			},
+		},
+	}
+}
+
+func (r *ruleStateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData))
+		return
+	}
+	r.client = c
+}
+
+func (r *ruleStateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ruleStateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *ruleStateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ruleStateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := r.client.GetRule(state.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading rule", err.Error())
+		return
+	}
+	state.ID = types.StringValue(rule.UUID)
+	state.State = types.StringValue(rule.State)
+	state.Enabled = types.BoolValue(rule.State == "ENABLED")
+	if state.DisableOnDestroy.IsNull() {
+		state.DisableOnDestroy = types.BoolValue(false) // Imported: match the schema default.
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *ruleStateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ruleStateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *ruleStateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ruleStateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || !state.DisableOnDestroy.ValueBool() {
+		return
+	}
+	if !checkWritable(r.client, "disable rule "+state.UUID.ValueString()+" on destroy", &resp.Diagnostics) {
+		return
+	}
+
+	if err := r.client.SetRuleState(state.UUID.ValueString(), false); err != nil {
+		resp.Diagnostics.AddError("Error disabling rule on destroy", err.Error())
+	}
+}
+
+func (r *ruleStateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("uuid"), req.ID)...)
+}
+
+// apply sets the rule state and records the result.
+func (r *ruleStateResource) apply(ctx context.Context, plan *ruleStateResourceModel, state *tfsdk.State, diags *diag.Diagnostics) {
+	uuid := plan.UUID.ValueString()
+	if !checkWritable(r.client, "set the state of rule "+uuid, diags) {
+		return
+	}
+	if err := r.client.SetRuleState(uuid, plan.Enabled.ValueBool()); err != nil {
+		diags.AddError("Error setting rule state", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(uuid)
+	if plan.Enabled.ValueBool() {
+		plan.State = types.StringValue("ENABLED")
+	} else {
+		plan.State = types.StringValue("DISABLED")
+	}
+	diags.Append(state.Set(ctx, plan)...)
+}
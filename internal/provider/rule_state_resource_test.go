@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRuleStateResource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckRuleResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRuleStateResourceConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("jira-automation_rule_state.test", "id", "jira-automation_rule.dep", "id"),
+					resource.TestCheckResourceAttr("jira-automation_rule_state.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("jira-automation_rule_state.test", "state", "DISABLED"),
+				),
+			},
+			{
+				Config: testAccRuleStateResourceConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("jira-automation_rule_state.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("jira-automation_rule_state.test", "state", "ENABLED"),
+				),
+			},
+			{
+				ResourceName:                         "jira-automation_rule_state.test",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "uuid",
+			},
+		},
+	})
+}
+
+func testAccRuleStateResourceConfig(enabled bool) string {
+	return fmt.Sprintf(`
+resource "jira-automation_rule" "dep" {
+  name       = "tf-acc-rule-state"
+  project_id = %[1]q
+
+  trigger = {
+    type = "status_transition"
+    args = {
+      from_status = "To Do"
+      to_status   = "In Progress"
+    }
+  }
+
+  components = [{
+    type = "log"
+    args = {
+      message = "tf-acc-test: rule state"
+    }
+  }]
+
+  # State is owned by jira-automation_rule_state below.
+  lifecycle {
+    ignore_changes = [enabled]
+  }
+}
+
+resource "jira-automation_rule_state" "test" {
+  uuid    = jira-automation_rule.dep.id
+  enabled = %[2]t
+}
+`, os.Getenv("JIRA_TEST_PROJECT_ID"), enabled)
+}
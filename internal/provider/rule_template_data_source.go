@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &ruleTemplateDataSource{}
+
+type ruleTemplateDataSource struct {
+	client *client.Client
+}
+
+type ruleTemplateDataSourceModel struct {
+	UUID           types.String `tfsdk:"uuid"`
+	Name           types.String `tfsdk:"name"`
+	Description    types.String `tfsdk:"description"`
+	ProjectID      types.String `tfsdk:"project_id"`
+	TriggerJSON    types.String `tfsdk:"trigger_json"`
+	ComponentsJSON types.String `tfsdk:"components_json"`
+}
+
+func NewRuleTemplateDataSource() datasource.DataSource {
+	return &ruleTemplateDataSource{}
+}
+
+func (d *ruleTemplateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rule_template"
+}
+
+func (d *ruleTemplateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing rule's trigger and components as normalized JSON, to use as a starting point for a new rule. " +
+			"The rule is not managed: decode the JSON with jsondecode(), change it, and pass it to a new jira-automation_rule.",
+		Attributes: map[string]schema.Attribute{
+			"uuid": schema.StringAttribute{
+				Required:    true,
+				Description: "UUID of the rule to copy.",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name of the source rule.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Description of the source rule; empty if it has none.",
+			},
+			"project_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Project ID from the source rule's scope; empty for site-wide rules.",
+			},
+			"trigger_json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The trigger as normalized JSON, without the ids the API assigns.",
+			},
+			"components_json": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				Description: "The components as a normalized JSON array, without the ids the API assigns. " +
+					"Sensitive because outgoing webhooks can carry credentials in their headers.",
+			},
+		},
+	}
+}
+
+func (d *ruleTemplateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData))
+		return
+	}
+	d.client = c
+}
+
+func (d *ruleTemplateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ruleTemplateDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, err := d.client.GetRule(state.UUID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read rule", err.Error())
+		return
+	}
+
+	trigger, components, err := templateJSON(d.client, rule)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to normalize rule JSON", err.Error())
+		return
+	}
+
+	var projectID string
+	if len(rule.RuleScopeARIs) > 0 {
+		projectID = client.ExtractProjectID(rule.RuleScopeARIs[0])
+	}
+
+	state.Name = types.StringValue(rule.Name)
+	state.Description = types.StringValue(rule.Description)
+	state.ProjectID = types.StringValue(projectID)
+	state.TriggerJSON = types.StringValue(trigger)
+	state.ComponentsJSON = types.StringValue(components)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// templateJSON returns rule's trigger and components normalized like the rule
+// resource's trigger_json and components_json, with field IDs turned back into
+// aliases when resolve_aliases_in_json is enabled.
+func templateJSON(c *client.Client, rule *client.Rule) (string, string, error) {
+	trigger, err := normalizeRawJSON(rule.Trigger)
+	if err != nil {
+		return "", "", fmt.Errorf("normalizing trigger: %w", err)
+	}
+	components := "[]" // Not null, so the result can be appended to.
+	if len(rule.Components) > 0 {
+		components, err = normalizeRawJSONArray(rule.Components)
+		if err != nil {
+			return "", "", fmt.Errorf("normalizing components: %w", err)
+		}
+	}
+	if !c.AliasRawJSON {
+		return trigger, components, nil
+	}
+
+	aliasedTrigger, err := unresolveAliasesInJSON(json.RawMessage(trigger), c.ReverseAliases)
+	if err != nil {
+		return "", "", fmt.Errorf("applying field aliases to trigger: %w", err)
+	}
+	aliasedComponents, err := unresolveAliasesInJSON(json.RawMessage(components), c.ReverseAliases)
+	if err != nil {
+		return "", "", fmt.Errorf("applying field aliases to components: %w", err)
+	}
+	return string(aliasedTrigger), string(aliasedComponents), nil
+}
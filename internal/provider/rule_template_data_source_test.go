@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"terraform-provider-jira-automation/internal/client"
+)
+
+func TestTemplateJSON(t *testing.T) {
+	rule := &client.Rule{
+		Trigger: json.RawMessage(`{"id":"1","component":"TRIGGER","type":"jira.manual.trigger.issue","value":{}}`),
+		Components: []json.RawMessage{
+			json.RawMessage(`{"id":"2","parentId":null,"component":"ACTION","type":"codebarrel.action.log","value":"{{issue.customfield_10709}}"}`),
+		},
+	}
+	c := &client.Client{ReverseAliases: map[string]string{"customfield_10709": "release_version"}}
+
+	trigger, components, err := templateJSON(c, rule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"component":"TRIGGER","type":"jira.manual.trigger.issue","value":{}}`; trigger != want {
+		t.Errorf("trigger:\n got %s\nwant %s", trigger, want)
+	}
+	if want := `[{"component":"ACTION","type":"codebarrel.action.log","value":"{{issue.customfield_10709}}"}]`; components != want {
+		t.Errorf("components:\n got %s\nwant %s", components, want)
+	}
+
+	// Aliases only apply to the JSON when resolve_aliases_in_json is on.
+	c.AliasRawJSON = true
+	if _, components, _ = templateJSON(c, rule); components != `[{"component":"ACTION","type":"codebarrel.action.log","value":"{{issue.release_version}}"}]` {
+		t.Errorf("aliased components: got %s", components)
+	}
+
+	// A rule without components yields an empty array, not null.
+	rule.Components = nil
+	if _, components, _ = templateJSON(c, rule); components != "[]" {
+		t.Errorf("no components: got %s, want []", components)
+	}
+}
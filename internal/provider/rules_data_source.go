@@ -6,8 +6,10 @@ import (
 
 	"terraform-provider-jira-automation/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -18,7 +20,12 @@ type rulesDataSource struct {
 }
 
 type rulesDataSourceModel struct {
-	Rules []ruleSummaryModel `tfsdk:"rules"`
+	ProjectID    types.String       `tfsdk:"project_id"`
+	State        types.String       `tfsdk:"state"`
+	Trigger      types.String       `tfsdk:"trigger"`
+	NameContains types.String       `tfsdk:"name_contains"`
+	Label        types.String       `tfsdk:"label"`
+	Rules        []ruleSummaryModel `tfsdk:"rules"`
 }
 
 type ruleSummaryModel struct {
@@ -38,8 +45,36 @@ func (d *rulesDataSource) Metadata(_ context.Context, req datasource.MetadataReq
 
 func (d *rulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "Lists all Jira Automation rule summaries.",
+		Description: "Lists Jira Automation rule summaries, optionally filtered.",
 		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Jira project numeric ID. When set, only that project's rules are listed, by searching on the project's scope instead of listing the whole site.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(numericIDPattern,
+						"must be the numeric project ID (e.g. 10001), not the project key"),
+				},
+			},
+			"state": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list rules in this state: ENABLED or DISABLED.",
+				Validators:  []validator.String{stringvalidator.OneOf("ENABLED", "DISABLED")},
+			},
+			"trigger": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list rules with this trigger type (e.g. jira.issue.event.trigger:created). Filtered by the API's rule search.",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"name_contains": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list rules whose name contains this text, ignoring case.",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
+			"label": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list rules carrying this label name (e.g. managed-by:terraform), matched against the labels in the rule summaries.",
+				Validators:  []validator.String{stringvalidator.LengthAtLeast(1)},
+			},
 			"rules": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of automation rule summaries.",
@@ -81,14 +116,25 @@ func (d *rulesDataSource) Configure(_ context.Context, req datasource.ConfigureR
 	d.client = c
 }
 
-func (d *rulesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
-	rules, err := d.client.ListRules()
+func (d *rulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state rulesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := d.client.SearchRules(client.RuleFilter{
+		ProjectID: state.ProjectID.ValueString(),
+		State:     state.State.ValueString(),
+		Trigger:   state.Trigger.ValueString(),
+		Name:      state.NameContains.ValueString(),
+		Label:     state.Label.ValueString(),
+	})
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to list rules", err.Error())
 		return
 	}
 
-	var state rulesDataSourceModel
 	for _, r := range rules {
 		state.Rules = append(state.Rules, ruleSummaryModel{
 			UUID:    types.StringValue(r.UUID),
@@ -23,6 +23,28 @@ func TestAccRulesDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestAccRulesDataSource_project(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheckWithProjectID(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRulesDataSourceConfig_basic() + fmt.Sprintf(`
+data "jira-automation_rules" "project" {
+  project_id = %q
+  depends_on = [jira-automation_rule.dep]
+}
+`, os.Getenv("JIRA_TEST_PROJECT_ID")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.jira-automation_rules.project", "rules.*", map[string]string{
+						"name": "tf-acc-datasource-dep",
+					}),
+				),
+			},
+		},
+	})
+}
+
 func testAccRulesDataSourceConfig_basic() string {
 	return fmt.Sprintf(`
 # Create a rule so the data source has something to find.
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &statusTransitionFunction{}
+
+// statusTransitionFunction builds the trigger_json of a status_transition
+// trigger, so rules in the JSON form get the same checks as the structured
+// trigger block.
+type statusTransitionFunction struct{}
+
+func NewStatusTransitionFunction() function.Function {
+	return &statusTransitionFunction{}
+}
+
+func (f *statusTransitionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "status_transition"
+}
+
+func (f *statusTransitionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the trigger_json of a status transition trigger.",
+		Description: "Returns the normalized trigger_json for a trigger that fires when an issue moves from one status to another, " +
+			"as the status_transition trigger type builds it. The API adds the rule's project scope on save, so the result doesn't depend on project_id.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "from_status",
+				Description: "Status name the issue leaves, or id:<status id>. ANY (or empty) matches every status.",
+			},
+			function.StringParameter{
+				Name:        "to_status",
+				Description: "Status name the issue enters, or id:<status id>. ANY (or empty) matches every status.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *statusTransitionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var from, to string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &from, &to))
+	if resp.Error != nil {
+		return
+	}
+
+	// normalizeRawJSON drops the eventFilters built from the empty cloud and
+	// project IDs; the API fills them in from the rule's scope on save.
+	raw, err := BuildTriggerJSON("status_transition", map[string]string{"from_status": from, "to_status": to}, "", "")
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	norm, err := normalizeRawJSON(raw)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, norm))
+}
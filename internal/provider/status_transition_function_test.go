@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func runStatusTransition(t *testing.T, from, to string) (string, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(from), types.StringValue(to)})}
+	resp := &function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	NewStatusTransitionFunction().Run(ctx, req, resp)
+	return resp.Result.Value().(types.String).ValueString(), resp.Error
+}
+
+func TestStatusTransitionFunction(t *testing.T) {
+	got, ferr := runStatusTransition(t, "To Do", "id:10001")
+	if ferr != nil {
+		t.Fatalf("unexpected error: %v", ferr)
+	}
+
+	// Same trigger_json as the structured trigger normalizes to.
+	raw, err := BuildTriggerJSON("status_transition", map[string]string{"from_status": "To Do", "to_status": "id:10001"}, "cloud-1", "10001")
+	if err != nil {
+		t.Fatalf("building trigger: %v", err)
+	}
+	want, _ := normalizeRawJSON(raw)
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	triggerType, args, err := ParseTrigger(json.RawMessage(got))
+	if err != nil {
+		t.Fatalf("parsing result: %v", err)
+	}
+	if triggerType != "status_transition" || args["from_status"] != "To Do" || args["to_status"] != "id:10001" {
+		t.Errorf("parsed %s %v, want the function's arguments back", triggerType, args)
+	}
+}
+
+func TestStatusTransitionFunction_AnyToAny(t *testing.T) {
+	_, ferr := runStatusTransition(t, "ANY", "")
+	if ferr == nil || !strings.Contains(ferr.Text, "from_status or to_status") {
+		t.Errorf("expected the trigger's validation error, got %v", ferr)
+	}
+}
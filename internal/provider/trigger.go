@@ -1,8 +1,13 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -11,6 +16,7 @@ import (
 type triggerModel struct {
 	Type types.String `tfsdk:"type"`
 	Args types.Map    `tfsdk:"args"`
+	When types.Map    `tfsdk:"when"`
 }
 
 // triggerBuilder builds the full API trigger JSON from user args.
@@ -21,6 +27,7 @@ type triggerParser func(raw json.RawMessage) (map[string]string, error)
 
 type triggerDef struct {
 	apiType string
+	args    []ArgSpec
 	build   triggerBuilder
 	parse   triggerParser
 }
@@ -29,8 +36,40 @@ type triggerDef struct {
 var triggerRegistry = map[string]triggerDef{
 	"status_transition": {
 		apiType: "jira.issue.event.trigger:transitioned",
-		build:   buildStatusTransition,
-		parse:   parseStatusTransition,
+		args: []ArgSpec{
+			{Name: "from_status", Description: "Status name the issue leaves, or id:<status id>. ANY (or empty) matches every status."},
+			{Name: "to_status", Description: "Status name the issue enters, or id:<status id>. ANY (or empty) matches every status."},
+			{Name: "event_key", Description: "Event key override (default jira:issue_updated)."},
+			{Name: "issue_event", Description: "Issue event override (default issue_generic)."},
+		},
+		build: buildStatusTransition,
+		parse: parseStatusTransition,
+	},
+	"scheduled": {
+		apiType: "jira.jql.scheduled",
+		args: []ArgSpec{
+			{Name: "cron", Required: true, Description: "Quartz cron expression."},
+			{Name: "jql", Description: "JQL query to run on each schedule."},
+			{Name: "run_for_each_issue", Description: "\"true\" to run once per issue the JQL returns; requires jql."},
+		},
+		build: buildScheduled,
+		parse: parseScheduled,
+	},
+	"manual": {
+		apiType: "jira.manual.trigger.issue",
+		args: []ArgSpec{
+			{Name: "value_json", Description: "The trigger's API value object (input prompts, groups, filters), e.g. from jsonencode(), passed through verbatim. Defaults to {}."},
+		},
+		build: passThroughTrigger("jira.manual.trigger.issue", 3),
+		parse: parsePassThroughTrigger,
+	},
+	"incoming_webhook": {
+		apiType: "jira.incoming.webhook",
+		args: []ArgSpec{
+			{Name: "value_json", Description: "The trigger's API value object (webhookToken, searchOrProvide, jql), e.g. from jsonencode(), passed through verbatim. Defaults to {}. webhook_url is built from its webhookToken."},
+		},
+		build: passThroughTrigger("jira.incoming.webhook", 1),
+		parse: parsePassThroughTrigger,
 	},
 }
 
@@ -43,12 +82,20 @@ var apiTypeToUserType = func() map[string]string {
 	return m
 }()
 
+// SupportedTriggerTypes returns the user-facing trigger types, sorted.
+func SupportedTriggerTypes() []string {
+	return slices.Sorted(maps.Keys(triggerRegistry))
+}
+
 // BuildTriggerJSON builds the full API trigger JSON for a given user-facing trigger type.
 func BuildTriggerJSON(triggerType string, args map[string]string, cloudID, projectID string) (json.RawMessage, error) {
 	def, ok := triggerRegistry[triggerType]
 	if !ok {
 		return nil, fmt.Errorf("unknown trigger type: %q", triggerType)
 	}
+	if err := validateArgs(triggerType, def.args, args); err != nil {
+		return nil, err
+	}
 	return def.build(args, cloudID, projectID)
 }
 
@@ -75,13 +122,185 @@ func ParseTrigger(raw json.RawMessage) (triggerType string, args map[string]stri
 	return userType, args, nil
 }
 
+// --- pass-through triggers ---
+
+// passThroughTrigger builds a trigger of apiType whose value is the
+// value_json arg verbatim, for triggers without structured args yet. This
+// lets rules with such triggers, e.g. ones whose input variables are used as
+// {{webhookData}}, use the trigger block.
+func passThroughTrigger(apiType string, schemaVersion int) triggerBuilder {
+	return func(args map[string]string, _, _ string) (json.RawMessage, error) {
+		value := json.RawMessage(`{}`)
+		if v, ok := args["value_json"]; ok {
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(v), &obj); err != nil || obj == nil {
+				return nil, fmt.Errorf("value_json must be a JSON object")
+			}
+			value = json.RawMessage(v)
+		}
+		return json.Marshal(map[string]interface{}{
+			"component":     "TRIGGER",
+			"conditions":    []interface{}{},
+			"connectionId":  nil,
+			"schemaVersion": schemaVersion,
+			"type":          apiType,
+			"value":         value,
+		})
+	}
+}
+
+// parsePassThroughTrigger reads a pass-through trigger's value into
+// value_json, which is left out when the value is empty.
+func parsePassThroughTrigger(raw json.RawMessage) (map[string]string, error) {
+	var trigger struct {
+		Value map[string]interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &trigger); err != nil {
+		return nil, fmt.Errorf("parsing trigger value: %w", err)
+	}
+	if len(trigger.Value) == 0 {
+		return map[string]string{}, nil
+	}
+	value, err := json.Marshal(trigger.Value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding trigger value: %w", err)
+	}
+	return map[string]string{"value_json": string(value)}, nil
+}
+
+// equivalentTriggerArgs returns prior's args when they describe the same
+// trigger as parsed's: for pass-through types when value_json is the same
+// value, so it keeps the config's formatting and key order and an omitted
+// value_json matches an empty value, and otherwise when both build the same
+// JSON, e.g. from_status = "ANY" for a side the API leaves unfiltered.
+func equivalentTriggerArgs(ctx context.Context, prior, parsed *triggerModel) types.Map {
+	if prior == nil || !prior.Type.Equal(parsed.Type) {
+		return parsed.Args
+	}
+	priorArgs, err := typesMapToStringMap(ctx, prior.Args)
+	if err != nil {
+		return parsed.Args
+	}
+	parsedArgs, err := typesMapToStringMap(ctx, parsed.Args)
+	if err != nil {
+		return parsed.Args
+	}
+	triggerType := parsed.Type.ValueString()
+	def, ok := triggerRegistry[triggerType]
+	if ok && len(def.args) == 1 && def.args[0].Name == "value_json" {
+		if len(priorArgs) > 1 || canonicalJSONObject(priorArgs["value_json"]) != canonicalJSONObject(parsedArgs["value_json"]) {
+			return parsed.Args
+		}
+		return prior.Args
+	}
+	priorRaw, err := BuildTriggerJSON(triggerType, priorArgs, "", "")
+	if err != nil {
+		return parsed.Args
+	}
+	parsedRaw, err := BuildTriggerJSON(triggerType, parsedArgs, "", "")
+	if err != nil || string(priorRaw) != string(parsedRaw) {
+		return parsed.Args
+	}
+	return prior.Args
+}
+
+// canonicalJSONObject re-encodes a JSON object with sorted keys, treating ""
+// as {}. Invalid JSON is returned as is.
+func canonicalJSONObject(s string) string {
+	if s == "" {
+		return "{}"
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	return string(out)
+}
+
+// nameRef is the {"type": "NAME", "value": ...} reference event triggers use
+// for statuses.
+type nameRef struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
 // --- status_transition ---
 
+// statusIDPrefix marks a status arg as a status ID (e.g. "id:10001") rather
+// than a name. IDs are stable across renames and unique across workflows.
+const statusIDPrefix = "id:"
+
+// anyStatus is the from_status/to_status value for "any status". That side
+// has no filter in the API trigger, and is left out of the args on read.
+const anyStatus = "ANY"
+
+// statusFilter returns the API filter for a from_status/to_status arg, or nil
+// for anyStatus and empty values.
+func statusFilter(v string) []nameRef {
+	if v == "" || v == anyStatus {
+		return nil
+	}
+	return []nameRef{statusRef(v)}
+}
+
+// statusRef turns a from_status/to_status arg into a status reference.
+func statusRef(v string) nameRef {
+	if id, ok := strings.CutPrefix(v, statusIDPrefix); ok {
+		return nameRef{Type: "ID", Value: id}
+	}
+	return nameRef{Type: "NAME", Value: v}
+}
+
+// statusArg is the inverse of statusRef.
+func statusArg(ref nameRef) string {
+	if ref.Type == "ID" {
+		return statusIDPrefix + ref.Value
+	}
+	return ref.Value
+}
+
+// Default event the status_transition trigger listens for. Some workflow
+// transitions emit other keys (e.g. jira:issue_moved); event_key and
+// issue_event override these.
+const (
+	defaultTransitionEventKey   = "jira:issue_updated"
+	defaultTransitionIssueEvent = "issue_generic"
+)
+
 func buildStatusTransition(args map[string]string, cloudID, projectID string) (json.RawMessage, error) {
 	fromStatus := args["from_status"]
 	toStatus := args["to_status"]
-	if fromStatus == "" || toStatus == "" {
-		return nil, fmt.Errorf("status_transition requires from_status and to_status args")
+	if statusFilter(fromStatus) == nil && statusFilter(toStatus) == nil {
+		return nil, fmt.Errorf("status_transition requires a from_status or to_status arg; both can't be %s", anyStatus)
+	}
+	if fromStatus == statusIDPrefix || toStatus == statusIDPrefix {
+		return nil, fmt.Errorf("status_transition: %q must be followed by a status ID", statusIDPrefix)
+	}
+	eventKey := defaultTransitionEventKey
+	if v := args["event_key"]; v != "" {
+		eventKey = v
+	}
+	issueEvent := defaultTransitionIssueEvent
+	if v := args["issue_event"]; v != "" {
+		issueEvent = v
+	}
+
+	value := map[string]interface{}{
+		"eventFilters": []string{
+			fmt.Sprintf("ari:cloud:jira:%s:project/%s", cloudID, projectID),
+		},
+		"eventKey":   eventKey,
+		"issueEvent": issueEvent,
+	}
+	if from := statusFilter(fromStatus); from != nil {
+		value["fromStatus"] = from
+	}
+	if to := statusFilter(toStatus); to != nil {
+		value["toStatus"] = to
 	}
 
 	trigger := map[string]interface{}{
@@ -90,19 +309,7 @@ func buildStatusTransition(args map[string]string, cloudID, projectID string) (j
 		"connectionId":  nil,
 		"schemaVersion": 1,
 		"type":          "jira.issue.event.trigger:transitioned",
-		"value": map[string]interface{}{
-			"eventFilters": []string{
-				fmt.Sprintf("ari:cloud:jira:%s:project/%s", cloudID, projectID),
-			},
-			"eventKey":   "jira:issue_updated",
-			"issueEvent": "issue_generic",
-			"fromStatus": []map[string]string{
-				{"type": "NAME", "value": fromStatus},
-			},
-			"toStatus": []map[string]string{
-				{"type": "NAME", "value": toStatus},
-			},
-		},
+		"value":         value,
 	}
 
 	return json.Marshal(trigger)
@@ -111,24 +318,107 @@ func buildStatusTransition(args map[string]string, cloudID, projectID string) (j
 func parseStatusTransition(raw json.RawMessage) (map[string]string, error) {
 	var trigger struct {
 		Value struct {
-			FromStatus []struct {
-				Value string `json:"value"`
-			} `json:"fromStatus"`
-			ToStatus []struct {
-				Value string `json:"value"`
-			} `json:"toStatus"`
+			FromStatus []nameRef `json:"fromStatus"`
+			ToStatus   []nameRef `json:"toStatus"`
+			EventKey   string    `json:"eventKey"`
+			IssueEvent string    `json:"issueEvent"`
 		} `json:"value"`
 	}
 	if err := json.Unmarshal(raw, &trigger); err != nil {
 		return nil, fmt.Errorf("parsing status_transition: %w", err)
 	}
 
+	// An unfiltered side is left out, like an omitted arg; a config that
+	// writes "ANY" keeps it via equivalentTriggerArgs.
 	args := map[string]string{}
 	if len(trigger.Value.FromStatus) > 0 {
-		args["from_status"] = trigger.Value.FromStatus[0].Value
+		args["from_status"] = statusArg(trigger.Value.FromStatus[0])
 	}
 	if len(trigger.Value.ToStatus) > 0 {
-		args["to_status"] = trigger.Value.ToStatus[0].Value
+		args["to_status"] = statusArg(trigger.Value.ToStatus[0])
+	}
+	// Omit the defaults so configs that leave event_key/issue_event unset round-trip.
+	if k := trigger.Value.EventKey; k != "" && k != defaultTransitionEventKey {
+		args["event_key"] = k
+	}
+	if e := trigger.Value.IssueEvent; e != "" && e != defaultTransitionIssueEvent {
+		args["issue_event"] = e
+	}
+
+	return args, nil
+}
+
+// --- scheduled ---
+
+// buildScheduled builds a cron-scheduled trigger. With run_for_each_issue the
+// trigger runs a JQL search and executes the rule once per matching issue.
+func buildScheduled(args map[string]string, _, _ string) (json.RawMessage, error) {
+	cron := args["cron"]
+	if cron == "" {
+		return nil, fmt.Errorf("scheduled requires a 'cron' arg")
+	}
+
+	forEachIssue := false
+	if v, ok := args["run_for_each_issue"]; ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled run_for_each_issue must be \"true\" or \"false\", got %q", v)
+		}
+		forEachIssue = b
+	}
+
+	jql := args["jql"]
+	if forEachIssue && jql == "" {
+		return nil, fmt.Errorf("scheduled run_for_each_issue requires a non-empty 'jql' arg")
+	}
+
+	executionMode := "nosearch"
+	if forEachIssue {
+		executionMode = "search"
+	}
+
+	trigger := map[string]interface{}{
+		"component":     "TRIGGER",
+		"conditions":    []interface{}{},
+		"connectionId":  nil,
+		"schemaVersion": 1,
+		"type":          "jira.jql.scheduled",
+		"value": map[string]interface{}{
+			"schedule": map[string]interface{}{
+				"method":         "CRON",
+				"cronExpression": cron,
+			},
+			"jql":               jql,
+			"executionMode":     executionMode,
+			"onlyUpdatedIssues": false,
+		},
+	}
+
+	return json.Marshal(trigger)
+}
+
+func parseScheduled(raw json.RawMessage) (map[string]string, error) {
+	var trigger struct {
+		Value struct {
+			Schedule struct {
+				CronExpression string `json:"cronExpression"`
+			} `json:"schedule"`
+			JQL           string `json:"jql"`
+			ExecutionMode string `json:"executionMode"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &trigger); err != nil {
+		return nil, fmt.Errorf("parsing scheduled: %w", err)
+	}
+
+	args := map[string]string{
+		"cron": trigger.Value.Schedule.CronExpression,
+	}
+	if trigger.Value.JQL != "" {
+		args["jql"] = trigger.Value.JQL
+	}
+	if trigger.Value.ExecutionMode == "search" {
+		args["run_for_each_issue"] = "true"
 	}
 
 	return args, nil
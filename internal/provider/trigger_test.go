@@ -1,8 +1,14 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 func TestBuildTriggerJSON_StatusTransition(t *testing.T) {
@@ -98,3 +104,382 @@ func TestParseTrigger_RoundTrip(t *testing.T) {
 		t.Errorf("to_status: got %q, want %q", gotArgs["to_status"], "In Progress")
 	}
 }
+
+func TestStatusTransition_AnyStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]string
+		wantFrom bool
+		wantTo   bool
+		want     map[string]string
+	}{
+		{"from any", map[string]string{"from_status": "ANY", "to_status": "Done"}, false, true,
+			map[string]string{"to_status": "Done"}},
+		{"to any", map[string]string{"from_status": "id:10001", "to_status": "ANY"}, true, false,
+			map[string]string{"from_status": "id:10001"}},
+		{"omitted side stays omitted", map[string]string{"to_status": "Done"}, false, true,
+			map[string]string{"to_status": "Done"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := BuildTriggerJSON("status_transition", tt.args, "cloud-123", "10001")
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			var trigger struct {
+				Value map[string]json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(raw, &trigger); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if _, ok := trigger.Value["fromStatus"]; ok != tt.wantFrom {
+				t.Errorf("fromStatus present: got %v, want %v", ok, tt.wantFrom)
+			}
+			if _, ok := trigger.Value["toStatus"]; ok != tt.wantTo {
+				t.Errorf("toStatus present: got %v, want %v", ok, tt.wantTo)
+			}
+
+			_, gotArgs, err := ParseTrigger(raw)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if !maps.Equal(gotArgs, tt.want) {
+				t.Errorf("parsed: got %v, want %v", gotArgs, tt.want)
+			}
+		})
+	}
+
+	for _, args := range []map[string]string{{}, {"from_status": "ANY", "to_status": "ANY"}} {
+		if _, err := BuildTriggerJSON("status_transition", args, "cloud-123", "10001"); err == nil {
+			t.Errorf("%v: expected error when neither side has a status", args)
+		}
+	}
+}
+
+func TestBuildTriggerJSON_ScheduledJQL(t *testing.T) {
+	args := map[string]string{
+		"cron":               "0 0 9 ? * MON-FRI",
+		"jql":                "project = ABC AND status = Open",
+		"run_for_each_issue": "true",
+	}
+
+	raw, err := BuildTriggerJSON("scheduled", args, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var trigger struct {
+		Type  string `json:"type"`
+		Value struct {
+			Schedule struct {
+				Method         string `json:"method"`
+				CronExpression string `json:"cronExpression"`
+			} `json:"schedule"`
+			JQL           string `json:"jql"`
+			ExecutionMode string `json:"executionMode"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &trigger); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if trigger.Type != "jira.jql.scheduled" {
+		t.Errorf("type: got %q, want %q", trigger.Type, "jira.jql.scheduled")
+	}
+	if trigger.Value.Schedule.Method != "CRON" || trigger.Value.Schedule.CronExpression != args["cron"] {
+		t.Errorf("schedule: got %+v", trigger.Value.Schedule)
+	}
+	if trigger.Value.JQL != args["jql"] {
+		t.Errorf("jql: got %q, want %q", trigger.Value.JQL, args["jql"])
+	}
+	if trigger.Value.ExecutionMode != "search" {
+		t.Errorf("executionMode: got %q, want %q", trigger.Value.ExecutionMode, "search")
+	}
+
+	gotType, gotArgs, err := ParseTrigger(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if gotType != "scheduled" {
+		t.Errorf("parsed type: got %q, want %q", gotType, "scheduled")
+	}
+	for k, want := range args {
+		if gotArgs[k] != want {
+			t.Errorf("parsed %s: got %q, want %q", k, gotArgs[k], want)
+		}
+	}
+}
+
+func TestBuildTriggerJSON_ScheduledValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]string
+	}{
+		{"missing cron", map[string]string{"jql": "project = ABC"}},
+		{"for each issue without jql", map[string]string{"cron": "0 0 9 * * ?", "run_for_each_issue": "true"}},
+		{"invalid bool", map[string]string{"cron": "0 0 9 * * ?", "jql": "project = ABC", "run_for_each_issue": "yes please"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildTriggerJSON("scheduled", tt.args, "", ""); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestStatusTransition_EventKeyOverride(t *testing.T) {
+	args := map[string]string{
+		"from_status": "To Do",
+		"to_status":   "Done",
+		"event_key":   "jira:issue_moved",
+		"issue_event": "issue_moved",
+	}
+
+	raw, err := BuildTriggerJSON("status_transition", args, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	var trigger struct {
+		Value struct {
+			EventKey   string `json:"eventKey"`
+			IssueEvent string `json:"issueEvent"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &trigger); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if trigger.Value.EventKey != "jira:issue_moved" || trigger.Value.IssueEvent != "issue_moved" {
+		t.Errorf("event: got %q/%q", trigger.Value.EventKey, trigger.Value.IssueEvent)
+	}
+
+	_, gotArgs, err := ParseTrigger(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	for k, want := range args {
+		if gotArgs[k] != want {
+			t.Errorf("%s: got %q, want %q", k, gotArgs[k], want)
+		}
+	}
+
+	// Defaults are not echoed back as args.
+	_, gotArgs, err = ParseTrigger(mustBuildTrigger(t, map[string]string{"from_status": "A", "to_status": "B"}))
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if _, ok := gotArgs["event_key"]; ok {
+		t.Errorf("default event_key should be omitted, got %v", gotArgs)
+	}
+	if _, ok := gotArgs["issue_event"]; ok {
+		t.Errorf("default issue_event should be omitted, got %v", gotArgs)
+	}
+}
+
+func mustBuildTrigger(t *testing.T, args map[string]string) json.RawMessage {
+	t.Helper()
+	raw, err := BuildTriggerJSON("status_transition", args, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+	return raw
+}
+
+func TestSupportedTriggerTypes(t *testing.T) {
+	got := SupportedTriggerTypes()
+	want := []string{"incoming_webhook", "manual", "scheduled", "status_transition"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPassThroughTriggers(t *testing.T) {
+	// A manual trigger with an input prompt round-trips through value_json.
+	value := `{"inputFromUsers":true,"inputPrompts":[{"label":"Version","variableName":"version"}]}`
+	raw, err := BuildTriggerJSON("manual", map[string]string{"value_json": value}, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("BuildTriggerJSON: %v", err)
+	}
+	var built map[string]interface{}
+	if err := json.Unmarshal(raw, &built); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if built["type"] != "jira.manual.trigger.issue" || built["schemaVersion"] != float64(3) {
+		t.Errorf("built: got type %v, schemaVersion %v", built["type"], built["schemaVersion"])
+	}
+	gotType, gotArgs, err := ParseTrigger(raw)
+	if err != nil {
+		t.Fatalf("ParseTrigger: %v", err)
+	}
+	if gotType != "manual" || gotArgs["value_json"] != value {
+		t.Errorf("round trip: got %s %v", gotType, gotArgs)
+	}
+
+	// Without value_json the value is empty, and reads back without args.
+	raw, err = BuildTriggerJSON("incoming_webhook", map[string]string{}, "", "")
+	if err != nil {
+		t.Fatalf("BuildTriggerJSON: %v", err)
+	}
+	if !strings.Contains(string(raw), `"value":{}`) {
+		t.Errorf("empty webhook trigger: got %s", raw)
+	}
+	if _, gotArgs, err := ParseTrigger(raw); err != nil || len(gotArgs) != 0 {
+		t.Errorf("empty webhook trigger args: got %v, %v", gotArgs, err)
+	}
+
+	// The token is kept, since webhook_url is built from it.
+	api := `{"component":"TRIGGER","type":"jira.incoming.webhook","value":{"webhookToken":"tok","searchOrProvide":"provided"}}`
+	gotType, gotArgs, err = ParseTrigger(json.RawMessage(api))
+	if err != nil {
+		t.Fatalf("ParseTrigger: %v", err)
+	}
+	if want := `{"searchOrProvide":"provided","webhookToken":"tok"}`; gotType != "incoming_webhook" || gotArgs["value_json"] != want {
+		t.Errorf("webhook: got %s %v, want value_json %s", gotType, gotArgs, want)
+	}
+
+	for _, v := range []string{`[]`, `null`, `nope`} {
+		if _, err := BuildTriggerJSON("manual", map[string]string{"value_json": v}, "", ""); err == nil {
+			t.Errorf("value_json %s: expected an error", v)
+		}
+	}
+}
+
+func TestEquivalentTriggerArgs(t *testing.T) {
+	ctx := context.Background()
+	model := func(typ string, args map[string]string) *triggerModel {
+		m, err := stringMapToTypesMap(ctx, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &triggerModel{Type: types.StringValue(typ), Args: m}
+	}
+	parsed := model("manual", map[string]string{"value_json": `{"groups":[],"inputFromUsers":false}`})
+
+	tests := []struct {
+		name      string
+		prior     *triggerModel
+		parsed    *triggerModel
+		wantPrior bool
+	}{
+		{"reformatted", model("manual", map[string]string{"value_json": "{\n  \"inputFromUsers\": false,\n  \"groups\": []\n}"}), parsed, true},
+		{"changed", model("manual", map[string]string{"value_json": `{"groups":["admins"],"inputFromUsers":false}`}), parsed, false},
+		{"omitted and empty", model("manual", map[string]string{}), model("manual", map[string]string{}), true},
+		{"empty object and empty", model("manual", map[string]string{"value_json": "{}"}), model("manual", map[string]string{}), true},
+		{"other type", model("incoming_webhook", map[string]string{"value_json": `{"groups":[],"inputFromUsers":false}`}), parsed, false},
+		{"explicit ANY status", model("status_transition", map[string]string{"from_status": "ANY", "to_status": "Done"}), model("status_transition", map[string]string{"to_status": "Done"}), true},
+		{"changed status", model("status_transition", map[string]string{"from_status": "ANY", "to_status": "Done"}), model("status_transition", map[string]string{"to_status": "Closed"}), false},
+		{"imported", nil, parsed, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := equivalentTriggerArgs(ctx, tc.prior, tc.parsed)
+			want := tc.parsed.Args
+			if tc.wantPrior {
+				want = tc.prior.Args
+			}
+			if !got.Equal(want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStatusTransition_StatusByID(t *testing.T) {
+	args := map[string]string{
+		"from_status": "id:10001",
+		"to_status":   "Done",
+	}
+
+	raw, err := BuildTriggerJSON("status_transition", args, "cloud-123", "10001")
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	var trigger struct {
+		Value struct {
+			FromStatus []nameRef `json:"fromStatus"`
+			ToStatus   []nameRef `json:"toStatus"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &trigger); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if want := (nameRef{Type: "ID", Value: "10001"}); len(trigger.Value.FromStatus) != 1 || trigger.Value.FromStatus[0] != want {
+		t.Errorf("fromStatus: got %v, want [%v]", trigger.Value.FromStatus, want)
+	}
+	if want := (nameRef{Type: "NAME", Value: "Done"}); len(trigger.Value.ToStatus) != 1 || trigger.Value.ToStatus[0] != want {
+		t.Errorf("toStatus: got %v, want [%v]", trigger.Value.ToStatus, want)
+	}
+
+	_, gotArgs, err := ParseTrigger(raw)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if gotArgs["from_status"] != "id:10001" || gotArgs["to_status"] != "Done" {
+		t.Errorf("round-trip args: got %v", gotArgs)
+	}
+
+	args["from_status"] = "id:"
+	if _, err := BuildTriggerJSON("status_transition", args, "cloud-123", "10001"); err == nil {
+		t.Error("expected error for empty status ID")
+	}
+}
+
+func TestTriggerWhen_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		when map[string]string
+		want string
+	}{
+		{
+			name: "jql",
+			when: map[string]string{"jql": "project = OPS AND priority = High"},
+			want: `"type":"jira.jql.condition","value":{"jql":"project = OPS AND priority = High"}`,
+		},
+		{
+			name: "comparator",
+			when: map[string]string{"first": "{{issue.priority.name}}", "operator": "equals", "second": "High"},
+			want: `"operator":"EQUALS"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := BuildTriggerJSON("status_transition", map[string]string{"from_status": "To Do", "to_status": "Done"}, "cloud-123", "10001")
+			if err != nil {
+				t.Fatalf("build error: %v", err)
+			}
+			raws, err := attachWhen([]json.RawMessage{raw}, tt.when)
+			if err != nil {
+				t.Fatalf("attach error: %v", err)
+			}
+			if !strings.Contains(string(raws[0]), tt.want) {
+				t.Errorf("expected %s in %s", tt.want, raws[0])
+			}
+
+			// The condition doesn't change how the trigger itself parses.
+			if _, args, err := ParseTrigger(raws[0]); err != nil || args["to_status"] != "Done" {
+				t.Errorf("parse trigger: args %v, err %v", args, err)
+			}
+			when, err := parseWhen(raws[0], nil)
+			if err != nil {
+				t.Fatalf("parse when: %v", err)
+			}
+			got, _ := typesMapToStringMap(context.Background(), when)
+			if !maps.Equal(got, tt.when) {
+				t.Errorf("when: got %v, want %v", got, tt.when)
+			}
+		})
+	}
+}
+
+func TestWhen_JQLRejectsOtherKeys(t *testing.T) {
+	for _, when := range []map[string]string{
+		{"jql": ""},
+		{"jql": "project = OPS", "first": "a"},
+	} {
+		if _, err := whenCondition(when); err == nil {
+			t.Errorf("%v: expected error", when)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-jira-automation/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &whoamiDataSource{}
+
+type whoamiDataSource struct {
+	client *client.Client
+}
+
+type whoamiDataSourceModel struct {
+	AccountID   types.String `tfsdk:"account_id"`
+	DisplayName types.String `tfsdk:"display_name"`
+}
+
+func NewWhoamiDataSource() datasource.DataSource {
+	return &whoamiDataSource{}
+}
+
+func (d *whoamiDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_whoami"
+}
+
+func (d *whoamiDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Returns the Jira user the provider authenticates as. Useful as a credentials and connectivity check.",
+		Attributes: map[string]schema.Attribute{
+			"account_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Account ID of the authenticated user.",
+			},
+			"display_name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Display name of the authenticated user.",
+			},
+		},
+	}
+}
+
+func (d *whoamiDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	c, ok := req.ProviderData.(*client.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *client.Client, got: %T", req.ProviderData))
+		return
+	}
+	d.client = c
+}
+
+func (d *whoamiDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	me, err := d.client.WhoAmI()
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to fetch current user", err.Error())
+		return
+	}
+
+	state := whoamiDataSourceModel{
+		AccountID:   types.StringValue(me.AccountID),
+		DisplayName: types.StringValue(me.DisplayName),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}